@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vbcli/internal/boardimage"
+	"vbcli/internal/codec"
+	"vbcli/internal/flap"
+	"vbcli/vestaboard"
+)
+
+// animationFrame has exactly one of Message and Characters set.
+type animationFrame struct {
+	Message    string  `json:"message,omitempty"`
+	Characters [][]int `json:"characters,omitempty"`
+	Duration   string  `json:"duration,omitempty"`
+}
+
+type animateOptions struct {
+	out        string
+	scale      int
+	duration   time.Duration
+	transition string
+	speed      string
+}
+
+func newAnimateCmd(stdin io.Reader, stdout, stderr io.Writer, opts *options) *cobra.Command {
+	var animateOpts animateOptions
+	animateCmd := &cobra.Command{
+		Use:   "animate --out board.gif <frames-json-file|->",
+		Short: "Write an animated GIF of a sequence of messages",
+		Long: `Write an animated GIF of a sequence of messages.
+
+The frames file is a JSON array of objects with either "message" (a template,
+rendered like send) or "characters" (a characters matrix), and an optional
+"duration" such as "5s".`,
+		Args: exactArgsWithHelp(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAnimate(cmd, stdin, stdout, stderr, opts, args[0], animateOpts)
+		},
+	}
+	animateCmd.Flags().StringVarP(&animateOpts.out, "out", "o", "", "Output GIF file")
+	animateCmd.Flags().IntVar(&animateOpts.scale, "scale", 1, "Scale factor for the image dimensions")
+	animateCmd.Flags().DurationVar(&animateOpts.duration, "duration", 3*time.Second, "How long each frame is shown when the file does not say")
+	animateCmd.Flags().StringVar(&animateOpts.transition, "transition", "", "Simulate flap transitions between frames: classic, wave, drift, curtain")
+	animateCmd.Flags().StringVar(&animateOpts.speed, "speed", "fast", "Transition speed: fast or gentle")
+	animateCmd.Flags().StringVarP(&opts.model, flagModel, "m", "", "VBML model for message frames: flagship or note")
+	animateCmd.Flags().StringVarP(&opts.align, "align", "a", "center", "VBML align for message frames: top, center, or bottom")
+	animateCmd.Flags().StringVarP(&opts.justify, "justify", "j", "center", "VBML justify for message frames: left, center, right, or justified")
+	return animateCmd
+}
+
+func runAnimate(cmd *cobra.Command, stdin io.Reader, stdout, stderr io.Writer, opts *options, path string, animateOpts animateOptions) error {
+	if strings.ToLower(filepath.Ext(animateOpts.out)) != ".gif" {
+		return usageError(cmd, fmt.Errorf("invalid --out %q (expected a .gif file)", animateOpts.out))
+	}
+	if animateOpts.scale < 1 {
+		return usageError(cmd, fmt.Errorf("invalid --scale %d (must be at least 1)", animateOpts.scale))
+	}
+	var transition flap.Options
+	if animateOpts.transition != "" {
+		transition = flap.Options{
+			Type:  strings.ToLower(strings.TrimSpace(animateOpts.transition)),
+			Speed: strings.ToLower(strings.TrimSpace(animateOpts.speed)),
+		}
+		if err := transition.Validate(); err != nil {
+			return usageError(cmd, err)
+		}
+	}
+
+	data, err := readFileArg(stdin, path)
+	if err != nil {
+		return err
+	}
+	var entries []animationFrame
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return usageError(cmd, fmt.Errorf("frames file must be a JSON array of frames: %w", err))
+	}
+	if len(entries) == 0 {
+		return usageError(cmd, errors.New("frames file has no frames"))
+	}
+
+	formatter, err := buildFormatter(stderr, opts, opts.board)
+	if err != nil {
+		return err
+	}
+	frames := make([]flap.Frame, 0, len(entries))
+	model := ""
+	for i, entry := range entries {
+		frame, frameModel, err := renderAnimationFrame(cmd, formatter, opts, entry, animateOpts.duration)
+		if err != nil {
+			return fmt.Errorf("frame %d: %w", i+1, err)
+		}
+		if model == "" {
+			model = frameModel
+		}
+		frames = append(frames, frame)
+	}
+	if transition.Type != "" {
+		frames, err = flap.Sequence(frames, transition)
+		if err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := boardimage.GIF(&buf, frames, boardimage.Options{Codec: codec.ForModel(model), Scale: animateOpts.scale}); err != nil {
+		return err
+	}
+	if err := os.WriteFile(animateOpts.out, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write image: %w", err)
+	}
+	if _, err := fmt.Fprintln(stdout, animateOpts.out); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func renderAnimationFrame(cmd *cobra.Command, formatter vestaboard.Formatter, opts *options, entry animationFrame, defaultDuration time.Duration) (flap.Frame, string, error) {
+	duration := defaultDuration
+	if entry.Duration != "" {
+		parsed, err := time.ParseDuration(entry.Duration)
+		if err != nil || parsed <= 0 {
+			return flap.Frame{}, "", usageError(cmd, fmt.Errorf("invalid duration %q", entry.Duration))
+		}
+		duration = parsed
+	}
+
+	switch {
+	case entry.Message != "" && entry.Characters != nil:
+		return flap.Frame{}, "", usageError(cmd, errors.New("set either message or characters, not both"))
+	case entry.Characters != nil:
+		table, err := codecFor(opts.model, entry.Characters)
+		if err != nil {
+			return flap.Frame{}, "", usageError(cmd, err)
+		}
+		return flap.Frame{Layout: entry.Characters, Duration: duration}, table.Model(), nil
+	case entry.Message != "":
+		characters, model, err := renderInput(cmd, formatter, opts, entry.Message)
+		if err != nil {
+			return flap.Frame{}, "", err
+		}
+		return flap.Frame{Layout: characters, Duration: duration}, model, nil
+	default:
+		return flap.Frame{}, "", usageError(cmd, errors.New("frame needs a message or characters"))
+	}
+}
+
+func readFileArg(stdin io.Reader, path string) ([]byte, error) {
+	if path == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("read input: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAnimateWritesFrames(t *testing.T) {
+	t.Parallel()
+
+	out := filepath.Join(t.TempDir(), "seq.gif")
+	frames := `[{"characters":[[1,2]],"duration":"2s"},{"message":"hi"},{"characters":[[63,0]],"duration":"500ms"}]`
+	if _, err := runRoot(t, frames, nil, "--renderer", "local", "animate", "-m", "note", "--out", out, "-"); err != nil {
+		t.Fatalf("animate: %v", err)
+	}
+	decoded := decodeGIF(t, out)
+	if len(decoded.Image) != 3 {
+		t.Fatalf("got %d frames, want 3", len(decoded.Image))
+	}
+	if decoded.Delay[0] != 200 || decoded.Delay[1] != 300 || decoded.Delay[2] != 50 {
+		t.Fatalf("delays = %v", decoded.Delay)
+	}
+}
+
+func TestAnimateWithTransition(t *testing.T) {
+	t.Parallel()
+
+	out := filepath.Join(t.TempDir(), "seq.gif")
+	frames := `[{"characters":[[0,0]]},{"characters":[[3,1]]}]`
+	if _, err := runRoot(t, frames, nil, "animate", "--transition", "wave", "--out", out, "-"); err != nil {
+		t.Fatalf("animate: %v", err)
+	}
+	// Column 0 needs three flaps, column 1 starts one step later and needs one.
+	decoded := decodeGIF(t, out)
+	if len(decoded.Image) != 4 {
+		t.Fatalf("got %d frames, want 4", len(decoded.Image))
+	}
+}
+
+func TestAnimateRejectsBadInput(t *testing.T) {
+	t.Parallel()
+
+	out := filepath.Join(t.TempDir(), "seq.gif")
+	tests := []struct {
+		frames string
+		args   []string
+	}{
+		{frames: `[{"characters":[[1]]}]`, args: []string{"animate", "--out", "seq.png", "-"}},
+		{frames: `[]`, args: []string{"animate", "--out", out, "-"}},
+		{frames: `{"characters":[[1]]}`, args: []string{"animate", "--out", out, "-"}},
+		{frames: `[{"characters":[[1]],"duration":"soon"}]`, args: []string{"animate", "--out", out, "-"}},
+		{frames: `[{"message":"hi","characters":[[1]]}]`, args: []string{"animate", "--out", out, "-"}},
+		{frames: `[{}]`, args: []string{"animate", "--out", out, "-"}},
+		{frames: `[{"characters":[[1]]}]`, args: []string{"animate", "--transition", "spin", "--out", out, "-"}},
+	}
+	for _, tc := range tests {
+		if _, err := runRoot(t, tc.frames, nil, tc.args...); err == nil {
+			t.Fatalf("expected error for %s %v", tc.frames, tc.args)
+		}
+	}
+	if _, err := os.Stat(out); err == nil {
+		t.Fatal("no image should be written")
+	}
+}
+
+func decodeGIF(t *testing.T, path string) *gif.GIF {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer file.Close()
+	decoded, err := gif.DecodeAll(file)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return decoded
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vbcli/internal/vbml"
+	"vbcli/vestaboard"
+)
+
+const (
+	clockFormat24h = "24h"
+	clockFormat12h = "12h"
+)
+
+type clockOptions struct {
+	zones      []string
+	format     string
+	date       bool
+	dateFormat string
+	once       bool
+}
+
+type clockZone struct {
+	Label    string
+	Location *time.Location
+}
+
+func newClockCmd(stdout, stderr io.Writer, opts *options) *cobra.Command {
+	var clockOpts clockOptions
+	clockCmd := &cobra.Command{
+		Use:   "clock",
+		Short: "Keep the current time and date on the board",
+		Long: `Keep the current time and date on the board.
+
+Each --zone adds a row to a world clock, such as "NYC 09:14 FRI". Without
+--zone the board shows the local time with the date below it. The board is
+updated on minute boundaries, or every --min-interval rounded up to whole
+minutes, counted on the wall clock of the first --zone. Frames are rendered
+locally, so no VBML API calls are made.`,
+		Args: exactArgsWithHelp(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runClock(cmd, stdout, stderr, opts, clockOpts)
+		},
+	}
+	clockCmd.Flags().StringArrayVar(&clockOpts.zones, "zone", nil, "Time zone row as LABEL=Area/City or Area/City; repeat for a world clock")
+	clockCmd.Flags().StringVar(&clockOpts.format, "format", clockFormat24h, "Hour format: 24h or 12h")
+	clockCmd.Flags().BoolVar(&clockOpts.date, "date", true, "Show the date, or the weekday on world clock rows")
+	clockCmd.Flags().StringVar(&clockOpts.dateFormat, "date-format", "", "Go time layout for the date (default \"Mon Jan 2\", or \"Mon\" on world clock rows)")
+	clockCmd.Flags().BoolVar(&clockOpts.once, "once", false, "Send the current time once and exit")
+	addForceFlag(clockCmd, opts)
+	clockCmd.Flags().StringVarP(&opts.model, flagModel, "m", "", "Board model: flagship or note")
+	clockCmd.Flags().StringVarP(&opts.align, "align", "a", "center", "VBML align: top, center, or bottom")
+	clockCmd.Flags().StringVarP(&opts.justify, "justify", "j", "center", "VBML justify: left, center, right, or justified")
+	return clockCmd
+}
+
+func runClock(cmd *cobra.Command, stdout, stderr io.Writer, opts *options, clockOpts clockOptions) error {
+	if clockOpts.format != clockFormat24h && clockOpts.format != clockFormat12h {
+		return usageError(cmd, fmt.Errorf("invalid --format %q (expected 24h or 12h)", clockOpts.format))
+	}
+	zones, err := parseClockZones(clockOpts.zones)
+	if err != nil {
+		return usageError(cmd, err)
+	}
+	style := messageStyle{Model: opts.model, Align: opts.align, Justify: opts.justify}
+	if err := style.validate(""); err != nil {
+		return usageError(cmd, err)
+	}
+	board, err := buildBoard(stderr, opts)
+	if err != nil {
+		return err
+	}
+	step := clockStep(opts.minInterval)
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	last := ""
+	for {
+		now := opts.clock.Now()
+		text := clockText(now, zones, clockOpts)
+		var held time.Time
+		if text != last {
+			held, err = showClockFrame(ctx, stdout, stderr, opts, board, style, now, text, clockOpts.once)
+			if err != nil {
+				return err
+			}
+			if held.IsZero() {
+				last = text
+			}
+		}
+		if clockOpts.once {
+			return nil
+		}
+		next := nextClockUpdate(now, step, clockLocation(now, zones))
+		if held.After(next) {
+			next = held
+		}
+		if err := sleepContext(ctx, opts.clock, next.Sub(now)); err != nil {
+			return nil
+		}
+	}
+}
+
+// showClockFrame returns the end of the quiet hours window that held the
+// frame, if one did. Failed sends are only logged, except with --once.
+func showClockFrame(ctx context.Context, stdout, stderr io.Writer, opts *options, board vestaboard.Board, style messageStyle, now time.Time, text string, once bool) (time.Time, error) {
+	characters, model, err := style.render(ctx, vbml.Renderer{}, text)
+	if err != nil {
+		return time.Time{}, err
+	}
+	stamp := now.Format(time.RFC3339)
+	err = deliver(ctx, stderr, opts, board, delivery{Command: "clock", Source: text, Model: model, Characters: characters})
+	switch {
+	case ctx.Err() != nil:
+		return time.Time{}, nil
+	case err != nil && once:
+		return time.Time{}, err
+	case err != nil:
+		warn(stderr, err)
+	}
+	held, _ := deferredUntil(err)
+	return held, logFiring(stdout, stamp, summarizeText(text), sendResult(err))
+}
+
+func clockText(now time.Time, zones []clockZone, clockOpts clockOptions) string {
+	if len(zones) == 0 {
+		zones = []clockZone{{Location: now.Location()}}
+	}
+	timeLayout := "15:04"
+	if clockOpts.format == clockFormat12h {
+		timeLayout = "3:04 PM"
+	}
+
+	if len(zones) == 1 {
+		local := now.In(zones[0].Location)
+		lines := []string{}
+		if zones[0].Label != "" {
+			lines = append(lines, zones[0].Label)
+		}
+		lines = append(lines, local.Format(timeLayout))
+		if clockOpts.date {
+			lines = append(lines, local.Format(dateLayout(clockOpts, "Mon Jan 2")))
+		}
+		return strings.ToUpper(strings.Join(lines, "\n"))
+	}
+
+	// Pad labels and times so the rows line up.
+	labelWidth, timeWidth := 0, 0
+	for _, zone := range zones {
+		labelWidth = max(labelWidth, len(zone.Label))
+		timeWidth = max(timeWidth, len(now.In(zone.Location).Format(timeLayout)))
+	}
+	lines := []string{}
+	for _, zone := range zones {
+		local := now.In(zone.Location)
+		line := fmt.Sprintf("%-*s %*s", labelWidth, zone.Label, timeWidth, local.Format(timeLayout))
+		if clockOpts.date {
+			line += " " + local.Format(dateLayout(clockOpts, "Mon"))
+		}
+		lines = append(lines, line)
+	}
+	return strings.ToUpper(strings.Join(lines, "\n"))
+}
+
+func dateLayout(clockOpts clockOptions, fallback string) string {
+	if clockOpts.dateFormat != "" {
+		return clockOpts.dateFormat
+	}
+	return fallback
+}
+
+// parseClockZones labels a zone without a label with its city.
+func parseClockZones(values []string) ([]clockZone, error) {
+	zones := []clockZone{}
+	for _, value := range values {
+		label, name, ok := strings.Cut(value, "=")
+		if !ok {
+			name = value
+			label = strings.ReplaceAll(path.Base(name), "_", " ")
+		}
+		location, err := time.LoadLocation(strings.TrimSpace(name))
+		if err != nil {
+			return nil, fmt.Errorf("invalid --zone %q: %w", value, err)
+		}
+		zones = append(zones, clockZone{Label: strings.TrimSpace(label), Location: location})
+	}
+	return zones, nil
+}
+
+func clockLocation(now time.Time, zones []clockZone) *time.Location {
+	if len(zones) > 0 {
+		return zones[0].Location
+	}
+	return now.Location()
+}
+
+// nextClockUpdate counts steps on the wall clock in loc, so an hourly clock
+// in Asia/Kolkata updates on its own hour.
+func nextClockUpdate(now time.Time, step time.Duration, loc *time.Location) time.Time {
+	_, offset := now.In(loc).Zone()
+	shift := time.Duration(offset) * time.Second
+	return now.Add(shift).Truncate(step).Add(step).Add(-shift)
+}
+
+func clockStep(minInterval time.Duration) time.Duration {
+	if minInterval <= time.Minute {
+		return time.Minute
+	}
+	return (minInterval + time.Minute - 1) / time.Minute * time.Minute
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"vbcli/vestaboard"
+)
+
+func TestClockText(t *testing.T) {
+	t.Parallel()
+
+	zones, err := parseClockZones([]string{"NYC=America/New_York", "LDN=Europe/London", "Asia/Tokyo"})
+	if err != nil {
+		t.Fatalf("parse zones: %v", err)
+	}
+	now := time.Date(2026, 10, 16, 13, 4, 0, 0, time.UTC)
+	tests := []struct {
+		zones     []clockZone
+		clockOpts clockOptions
+		want      string
+	}{
+		{zones: zones, clockOpts: clockOptions{format: clockFormat24h, date: true}, want: "NYC   09:04 FRI\nLDN   14:04 FRI\nTOKYO 22:04 FRI"},
+		{zones: zones, clockOpts: clockOptions{format: clockFormat12h}, want: "NYC    9:04 AM\nLDN    2:04 PM\nTOKYO 10:04 PM"},
+		{clockOpts: clockOptions{format: clockFormat24h, date: true}, want: "13:04\nFRI OCT 16"},
+		{zones: zones[2:], clockOpts: clockOptions{format: clockFormat12h, date: true, dateFormat: "2 Jan 2006"}, want: "TOKYO\n10:04 PM\n16 OCT 2026"},
+	}
+	for _, tc := range tests {
+		if got := clockText(now, tc.zones, tc.clockOpts); got != tc.want {
+			t.Fatalf("got %q, want %q", got, tc.want)
+		}
+	}
+
+	if _, err := parseClockZones([]string{"MARS=Mars/Olympus_Mons"}); err == nil {
+		t.Fatal("expected error for an unknown zone")
+	}
+}
+
+func TestClockOnce(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard(nil)
+	clock := newInstantClock(time.Date(2026, 10, 16, 13, 4, 30, 0, time.UTC))
+	out, err := runRoot(t, "", []Option{WithBoard(fake), WithClock(clock)}, "clock", "--once", "--zone", "NYC=America/New_York", "--zone", "LDN=Europe/London")
+	if err != nil {
+		t.Fatalf("clock: %v", err)
+	}
+	if want := []string{"NYC 09:04 FRI LDN 14:04 FRI"}; !reflect.DeepEqual(sentText(fake), want) {
+		t.Fatalf("sent %q, want %q", sentText(fake), want)
+	}
+	if out != "2026-10-16T13:04:30Z NYC 09:04 FRI LDN 14:04 FRI sent\n" {
+		t.Fatalf("unexpected log %q", out)
+	}
+	if len(clock.Slept()) != 0 {
+		t.Fatalf("--once slept %v", clock.Slept())
+	}
+}
+
+func TestClockUpdatesOnMinuteBoundaries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args  []string
+		waits []time.Duration
+	}{
+		{args: []string{"clock"}, waits: []time.Duration{40 * time.Second, time.Minute}},
+		{args: []string{"--min-interval", "90s", "clock"}, waits: []time.Duration{100 * time.Second, 2 * time.Minute}},
+	}
+	for _, tc := range tests {
+		fake := vestaboard.NewFakeBoard(nil)
+		clock := newStepClock(time.Date(2026, 10, 16, 9, 14, 20, 0, time.UTC))
+		stop := startRoot(t, []Option{WithBoard(fake), WithClock(clock)}, tc.args...)
+		for i, want := range tc.waits {
+			w := clock.next(t)
+			if w.d != want {
+				t.Fatalf("%v: wait %d is %s, want %s", tc.args, i, w.d, want)
+			}
+			if i < len(tc.waits)-1 {
+				clock.fire(w)
+			}
+		}
+		if _, err := stop(); err != nil {
+			t.Fatalf("%v: %v", tc.args, err)
+		}
+		if len(fake.Sent) != len(tc.waits) {
+			t.Fatalf("%v: sent %d frames, want %d", tc.args, len(fake.Sent), len(tc.waits))
+		}
+		if got := sentText(fake); !strings.HasPrefix(got[0], "09:14 FRI OCT 16") {
+			t.Fatalf("%v: first frame %q", tc.args, got[0])
+		}
+	}
+}
+
+func TestNextClockUpdateAlignsInDisplayZone(t *testing.T) {
+	t.Parallel()
+
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatalf("load zone: %v", err)
+	}
+	now := time.Date(2026, 10, 16, 9, 14, 20, 0, time.UTC)
+	tests := []struct {
+		step time.Duration
+		loc  *time.Location
+		want time.Time
+	}{
+		{step: time.Minute, loc: kolkata, want: time.Date(2026, 10, 16, 9, 15, 0, 0, time.UTC)},
+		{step: time.Hour, loc: time.UTC, want: time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)},
+		// 15:00 in Kolkata is 09:30 UTC.
+		{step: time.Hour, loc: kolkata, want: time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)},
+		{step: 2 * time.Hour, loc: kolkata, want: time.Date(2026, 10, 16, 10, 30, 0, 0, time.UTC)},
+	}
+	for _, tc := range tests {
+		if got := nextClockUpdate(now, tc.step, tc.loc); !got.Equal(tc.want) {
+			t.Fatalf("%s in %s: got %s, want %s", tc.step, tc.loc, got, tc.want)
+		}
+	}
+}
+
+func TestClockRejectsBadFlags(t *testing.T) {
+	t.Parallel()
+
+	tests := [][]string{
+		{"clock", "--format", "13h"},
+		{"clock", "--zone", "Mars/Olympus_Mons"},
+		{"clock", "--align", "middle"},
+		{"clock", "now"},
+	}
+	for _, args := range tests {
+		if _, err := runRoot(t, "", []Option{WithBoard(vestaboard.NewFakeBoard(nil))}, args...); err == nil {
+			t.Fatalf("%v: expected error", args)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vbcli/internal/vbml"
+	vbapi "vbcli/internal/vestaboard"
+	"vbcli/vestaboard"
+)
+
+// countdownTimeLayouts are read in the local time zone.
+var countdownTimeLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04", time.DateOnly}
+
+type liveOptions struct {
+	done    string
+	restore bool
+	hold    time.Duration
+}
+
+// liveTimer counts down to target, or up from start when target is zero.
+type liveTimer struct {
+	command string
+	label   string
+	start   time.Time
+	target  time.Time
+}
+
+func newCountdownCmd(stdout, stderr io.Writer, opts *options) *cobra.Command {
+	var (
+		to       string
+		liveOpts liveOptions
+	)
+	countdownCmd := &cobra.Command{
+		Use:   "countdown [label]",
+		Short: "Count down to a time on the board",
+		Long: `Count down to a time on the board.
+
+The remaining time is sent hourly while the end is days away, every minute
+while it is hours away, and every --min-interval in the last hour, or as
+often as the backend allows (15s on the cloud, 5s on the local network).
+When the time comes, the label is shown with --done. Frames are rendered
+locally, so no VBML API calls are made.`,
+		Args: maxArgsWithHelp(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := parseCountdownTime(to, opts.clock.Now().Location())
+			if err != nil {
+				return usageError(cmd, err)
+			}
+			if !target.After(opts.clock.Now()) {
+				return usageError(cmd, fmt.Errorf("--to %s is not in the future", to))
+			}
+			timer := liveTimer{command: "countdown", start: opts.clock.Now(), target: target}
+			if len(args) > 0 {
+				timer.label = args[0]
+			}
+			return runLiveTimer(cmd, stdout, stderr, opts, timer, liveOpts)
+		},
+	}
+	countdownCmd.Flags().StringVar(&to, "to", "", "End time, RFC 3339 or local 2006-01-02T15:04")
+	_ = countdownCmd.MarkFlagRequired("to")
+	addLiveFlags(countdownCmd, opts, &liveOpts, true)
+	return countdownCmd
+}
+
+func newTimerCmd(stdout, stderr io.Writer, opts *options) *cobra.Command {
+	var liveOpts liveOptions
+	timerCmd := &cobra.Command{
+		Use:   "timer <duration> [label]",
+		Short: "Count down a duration, such as 25m, on the board",
+		Long: `Count down a duration, such as 25m, on the board.
+
+Updates follow the same cadence as countdown.`,
+		Args: rangeArgsWithHelp(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			d, err := time.ParseDuration(args[0])
+			if err != nil || d <= 0 {
+				return usageError(cmd, fmt.Errorf("invalid duration %q (expected a positive duration such as 25m)", args[0]))
+			}
+			now := opts.clock.Now()
+			timer := liveTimer{command: "timer", start: now, target: now.Add(d)}
+			if len(args) > 1 {
+				timer.label = args[1]
+			}
+			return runLiveTimer(cmd, stdout, stderr, opts, timer, liveOpts)
+		},
+	}
+	addLiveFlags(timerCmd, opts, &liveOpts, true)
+	return timerCmd
+}
+
+func newStopwatchCmd(stdout, stderr io.Writer, opts *options) *cobra.Command {
+	var liveOpts liveOptions
+	stopwatchCmd := &cobra.Command{
+		Use:   "stopwatch [label]",
+		Short: "Show the time elapsed since it started until interrupted",
+		Long: `Show the time elapsed since it started until interrupted.
+
+The elapsed time is sent every --min-interval, or as often as the backend
+allows, in the first hour, then every minute, and hourly after two days.
+SIGINT and SIGTERM stop it.`,
+		Args: maxArgsWithHelp(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			timer := liveTimer{command: "stopwatch", start: opts.clock.Now()}
+			if len(args) > 0 {
+				timer.label = args[0]
+			}
+			return runLiveTimer(cmd, stdout, stderr, opts, timer, liveOpts)
+		},
+	}
+	addLiveFlags(stopwatchCmd, opts, &liveOpts, false)
+	return stopwatchCmd
+}
+
+func addLiveFlags(cmd *cobra.Command, opts *options, liveOpts *liveOptions, ends bool) {
+	cmd.Flags().StringVarP(&opts.model, flagModel, "m", "", "VBML model: flagship or note")
+	cmd.Flags().StringVarP(&opts.align, "align", "a", "center", "VBML align: top, center, or bottom")
+	cmd.Flags().StringVarP(&opts.justify, "justify", "j", "center", "VBML justify: left, center, right, or justified")
+	addForceFlag(cmd, opts)
+	if ends {
+		cmd.Flags().StringVar(&liveOpts.done, "done", "DONE", "Text shown under the label when the time is up")
+		cmd.Flags().BoolVar(&liveOpts.restore, "restore", false, "Restore the previous message --hold after the time is up, or when interrupted")
+		cmd.Flags().DurationVar(&liveOpts.hold, "hold", time.Minute, "With --restore, how long to show the done frame")
+	} else {
+		cmd.Flags().BoolVar(&liveOpts.restore, "restore", false, "Restore the previous message when stopped")
+	}
+}
+
+func runLiveTimer(cmd *cobra.Command, stdout, stderr io.Writer, opts *options, timer liveTimer, liveOpts liveOptions) error {
+	if liveOpts.hold < 0 {
+		return usageError(cmd, fmt.Errorf("invalid --hold %s (must be 0 or more)", liveOpts.hold))
+	}
+	style := messageStyle{Model: opts.model, Align: opts.align, Justify: opts.justify}
+	if err := style.validate(timer.label); err != nil {
+		return usageError(cmd, err)
+	}
+	board, err := buildBoard(stderr, opts)
+	if err != nil {
+		return err
+	}
+	interval, err := sendInterval(opts)
+	if err != nil {
+		return err
+	}
+	finest := finestStep(interval)
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var revert temporaryRevert
+	if liveOpts.restore {
+		previous, err := board.GetCurrentState(ctx)
+		if err != nil {
+			return err
+		}
+		revert.Previous, revert.PreviousModel = previous.Layout, previous.Model
+	}
+	restore := func() error {
+		if !liveOpts.restore || revert.Shown == nil {
+			return nil
+		}
+		return restorePrevious(context.WithoutCancel(ctx), stderr, opts, board, timer.command, revert)
+	}
+
+	for {
+		text, wait, finished := timer.frame(opts.clock.Now(), finest)
+		if finished {
+			break
+		}
+		held, err := showLiveFrame(ctx, stdout, stderr, opts, board, style, timer, text, &revert)
+		if err != nil {
+			return err
+		}
+		wait = max(wait, held.Sub(opts.clock.Now()))
+		if err := sleepContext(ctx, opts.clock, wait); err != nil {
+			return restore()
+		}
+	}
+
+	for {
+		held, err := showLiveFrame(ctx, stdout, stderr, opts, board, style, timer, liveOpts.done, &revert)
+		if err != nil {
+			return err
+		}
+		if held.IsZero() {
+			break
+		}
+		if err := sleepContext(ctx, opts.clock, held.Sub(opts.clock.Now())); err != nil {
+			return restore()
+		}
+	}
+	if !liveOpts.restore {
+		return nil
+	}
+	_ = sleepContext(ctx, opts.clock, liveOpts.hold)
+	return restore()
+}
+
+// showLiveFrame returns the end of the quiet hours window that held the
+// frame, if one did. Failed sends are only logged.
+func showLiveFrame(ctx context.Context, stdout, stderr io.Writer, opts *options, board vestaboard.Board, style messageStyle, timer liveTimer, text string, revert *temporaryRevert) (time.Time, error) {
+	message := text
+	if timer.label != "" {
+		message = timer.label + "\n" + text
+	}
+	characters, model, err := style.render(ctx, vbml.Renderer{}, message)
+	if err != nil {
+		if ctx.Err() != nil {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	stamp := opts.clock.Now().Format(time.RFC3339)
+	err = deliver(ctx, stderr, opts, board, delivery{Command: timer.command, Source: message, Model: model, Characters: characters})
+	if ctx.Err() != nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		warn(stderr, err)
+	} else {
+		revert.Shown = characters
+	}
+	held, _ := deferredUntil(err)
+	return held, logFiring(stdout, stamp, text, sendResult(err))
+}
+
+// frame returns the text at now and how long it stays correct. Countdowns
+// round up, so they never show less time than is left.
+func (timer liveTimer) frame(now time.Time, finest time.Duration) (string, time.Duration, bool) {
+	if timer.target.IsZero() {
+		elapsed := now.Sub(timer.start)
+		step := liveStep(elapsed, finest)
+		shown := elapsed / step * step
+		return formatSpan(shown, step), shown + step - elapsed, false
+	}
+	left := timer.target.Sub(now)
+	if left <= 0 {
+		return "", 0, true
+	}
+	step := liveStep(left, finest)
+	shown := (left + step - 1) / step * step
+	return formatSpan(shown, step), left - (shown - step), false
+}
+
+// sendInterval is --min-interval, but never less than the backend allows.
+func sendInterval(opts *options) (time.Duration, error) {
+	backend, err := resolveBackend(opts.backend)
+	if err != nil {
+		return 0, err
+	}
+	if backend == backendLocal {
+		return max(opts.minInterval, vbapi.LocalSendInterval), nil
+	}
+	return max(opts.minInterval, vbapi.CloudSendInterval), nil
+}
+
+// finestStep rounds interval up to a whole unit of the display.
+func finestStep(interval time.Duration) time.Duration {
+	unit := time.Second
+	switch {
+	case interval > time.Hour:
+		unit = time.Hour
+	case interval > time.Minute:
+		unit = time.Minute
+	}
+	return (interval + unit - 1) / unit * unit
+}
+
+// liveStep is hourly above two days, per minute above an hour, and finest
+// below that.
+func liveStep(span, finest time.Duration) time.Duration {
+	step := finest
+	switch {
+	case span > 48*time.Hour:
+		step = time.Hour
+	case span > time.Hour:
+		step = time.Minute
+	}
+	return max(step, finest)
+}
+
+func formatSpan(d, step time.Duration) string {
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	seconds := int(d % time.Minute / time.Second)
+	switch {
+	case step < time.Minute && d >= time.Hour:
+		return fmt.Sprintf("%d:%02d:%02d", int(d/time.Hour), minutes, seconds)
+	case step < time.Minute:
+		return fmt.Sprintf("%d:%02d", minutes, seconds)
+	case step >= time.Hour && days > 0:
+		return fmt.Sprintf("%dD %dH", days, hours)
+	case step >= time.Hour:
+		return fmt.Sprintf("%dH", hours)
+	case days > 0:
+		return fmt.Sprintf("%dD %dH %02dM", days, hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dH %02dM", hours, minutes)
+	}
+	return fmt.Sprintf("%dM", minutes)
+}
+
+func parseCountdownTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range countdownTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --to %q (expected an RFC 3339 time or 2006-01-02T15:04)", value)
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"vbcli/internal/codec"
+	"vbcli/vestaboard"
+)
+
+// sentText decodes every layout sent to fake into one line of board text.
+func sentText(fake *vestaboard.FakeBoard) []string {
+	texts := []string{}
+	for _, layout := range fake.Sent {
+		texts = append(texts, strings.Join(strings.Fields(codec.ForModel("flagship").Decode(layout)), " "))
+	}
+	return texts
+}
+
+func TestLiveTimerFrame(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 12, 28, 0, 0, 0, 0, time.UTC)
+	target := time.Date(2026, 12, 31, 23, 59, 0, 0, time.UTC)
+	countdown := liveTimer{start: start, target: target}
+	stopwatch := liveTimer{start: start}
+	tests := []struct {
+		timer    liveTimer
+		at       time.Time
+		finest   time.Duration
+		text     string
+		wait     time.Duration
+		finished bool
+	}{
+		{timer: countdown, at: start, finest: 15 * time.Second, text: "4D 0H", wait: 59 * time.Minute},
+		{timer: countdown, at: time.Date(2026, 12, 29, 23, 59, 0, 0, time.UTC), finest: 15 * time.Second, text: "2D 0H 00M", wait: time.Minute},
+		{timer: countdown, at: time.Date(2026, 12, 31, 20, 30, 10, 0, time.UTC), finest: 15 * time.Second, text: "3H 29M", wait: 50 * time.Second},
+		{timer: countdown, at: time.Date(2026, 12, 31, 23, 54, 20, 0, time.UTC), finest: 15 * time.Second, text: "4:45", wait: 10 * time.Second},
+		{timer: countdown, at: time.Date(2026, 12, 31, 23, 54, 20, 0, time.UTC), finest: 2 * time.Minute, text: "6M", wait: 40 * time.Second},
+		{timer: countdown, at: target, finest: 15 * time.Second, finished: true},
+		{timer: stopwatch, at: start.Add(10 * time.Second), finest: 15 * time.Second, text: "0:00", wait: 5 * time.Second},
+		{timer: stopwatch, at: start.Add(time.Hour + 90*time.Second), finest: 15 * time.Second, text: "1H 01M", wait: 30 * time.Second},
+		{timer: stopwatch, at: start.Add(50 * time.Hour), finest: 15 * time.Second, text: "2D 2H", wait: time.Hour},
+	}
+	for _, tc := range tests {
+		text, wait, finished := tc.timer.frame(tc.at, tc.finest)
+		if text != tc.text || wait != tc.wait || finished != tc.finished {
+			t.Fatalf("%s: got %q %s %v, want %q %s %v", tc.at, text, wait, finished, tc.text, tc.wait, tc.finished)
+		}
+	}
+}
+
+func TestFinestStep(t *testing.T) {
+	t.Parallel()
+
+	tests := map[time.Duration]time.Duration{
+		time.Second:             time.Second,
+		1500 * time.Millisecond: 2 * time.Second,
+		90 * time.Second:        2 * time.Minute,
+		90 * time.Minute:        2 * time.Hour,
+	}
+	for minInterval, want := range tests {
+		if got := finestStep(minInterval); got != want {
+			t.Fatalf("finestStep(%s) = %s, want %s", minInterval, got, want)
+		}
+	}
+}
+
+func TestSendIntervalFollowsBackend(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		backend     string
+		minInterval time.Duration
+		want        time.Duration
+	}{
+		{backend: "cloud", want: 15 * time.Second},
+		{backend: "local", want: 5 * time.Second},
+		{backend: "cloud", minInterval: time.Second, want: 15 * time.Second},
+		{backend: "local", minInterval: time.Minute, want: time.Minute},
+	}
+	for _, tc := range tests {
+		got, err := sendInterval(&options{backend: tc.backend, minInterval: tc.minInterval})
+		if err != nil || got != tc.want {
+			t.Fatalf("%s %s: got %s %v, want %s", tc.backend, tc.minInterval, got, err, tc.want)
+		}
+	}
+}
+
+func TestCountdownSendsFramesAndDone(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard(nil)
+	clock := newInstantClock(time.Date(2026, 12, 31, 23, 55, 0, 0, time.UTC))
+	rootOptions := []Option{WithBoard(fake), WithClock(clock)}
+	out, err := runRoot(t, "", rootOptions, "--min-interval", "1m", "countdown", "--to", "2026-12-31T23:59", "New Year")
+	if err != nil {
+		t.Fatalf("countdown: %v", err)
+	}
+	want := []string{"NEW YEAR 4M", "NEW YEAR 3M", "NEW YEAR 2M", "NEW YEAR 1M", "NEW YEAR DONE"}
+	if got := sentText(fake); !reflect.DeepEqual(got, want) {
+		t.Fatalf("sent %q, want %q", got, want)
+	}
+	if want := []time.Duration{time.Minute, time.Minute, time.Minute, time.Minute}; !reflect.DeepEqual(clock.Slept(), want) {
+		t.Fatalf("slept %v, want %v", clock.Slept(), want)
+	}
+	if !strings.HasPrefix(out, "2026-12-31T23:55:00Z 4M sent\n") || !strings.HasSuffix(out, "2026-12-31T23:59:00Z DONE sent\n") {
+		t.Fatalf("unexpected log:\n%s", out)
+	}
+}
+
+func TestTimerRestoresPreviousMessage(t *testing.T) {
+	t.Parallel()
+
+	previous := make([][]int, 6)
+	for i := range previous {
+		previous[i] = make([]int, 22)
+	}
+	previous[0][0] = 63
+	fake := vestaboard.NewFakeBoard(previous)
+	clock := newInstantClock(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	rootOptions := []Option{WithBoard(fake), WithClock(clock)}
+	if _, err := runRoot(t, "", rootOptions, "--renderer", "local", "timer", "30s", "--done", "Time's up", "--restore", "--hold", "2m"); err != nil {
+		t.Fatalf("timer: %v", err)
+	}
+	texts := sentText(fake)
+	if want := []string{"0:30", "0:15", "TIME'S UP"}; len(texts) != 4 || !reflect.DeepEqual(texts[:3], want) {
+		t.Fatalf("sent %q, want %q and the previous message", texts, want)
+	}
+	if !reflect.DeepEqual(fake.Sent[3], previous) {
+		t.Fatalf("restored %v, want %v", fake.Sent[3], previous)
+	}
+	if want := []time.Duration{15 * time.Second, 15 * time.Second, 2 * time.Minute}; !reflect.DeepEqual(clock.Slept(), want) {
+		t.Fatalf("slept %v, want %v", clock.Slept(), want)
+	}
+}
+
+func TestStopwatchRestoresWhenStopped(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{1, 2}, {3, 4}})
+	clock := newStepClock(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	stop := startRoot(t, []Option{WithBoard(fake), WithClock(clock)}, "--backend", "local", "stopwatch", "-m", "note", "--restore", "Lap")
+	w := clock.next(t)
+	clock.fire(w)
+	// The local backend allows a frame every 5s.
+	if w = clock.next(t); w.d != 5*time.Second {
+		t.Fatalf("wait %s, want 5s", w.d)
+	}
+	out, err := stop()
+	if err != nil {
+		t.Fatalf("stopwatch: %v", err)
+	}
+	if want := "2026-10-16T09:00:00Z 0:00 sent\n2026-10-16T09:00:05Z 0:05 sent\n"; out != want {
+		t.Fatalf("log %q, want %q", out, want)
+	}
+	if len(fake.Sent) != 3 || !reflect.DeepEqual(fake.Sent[2], [][]int{{1, 2}, {3, 4}}) {
+		t.Fatalf("expected two frames and the restored message, got %v", fake.Sent)
+	}
+}
+
+func TestLiveTimersRejectBadArguments(t *testing.T) {
+	t.Parallel()
+
+	clock := newInstantClock(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	tests := [][]string{
+		{"countdown", "New Year"},
+		{"countdown", "--to", "new year"},
+		{"countdown", "--to", "2026-10-16T08:00"},
+		{"timer", "soon"},
+		{"timer", "-5m"},
+		{"timer", "5m", "--hold", "-1s"},
+		{"stopwatch", "--align", "middle"},
+	}
+	for _, args := range tests {
+		if _, err := runRoot(t, "", []Option{WithBoard(vestaboard.NewFakeBoard(nil)), WithClock(clock)}, args...); err == nil {
+			t.Fatalf("%v: expected error", args)
+		}
+	}
+}
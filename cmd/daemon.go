@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vbcli/internal/queue"
+	"vbcli/internal/schedule"
+	"vbcli/vestaboard"
+)
+
+const (
+	queueBaselineFile = "queue-baseline.json"
+	// queueMaxBackoff is the longest wait before retrying a queued item
+	// whose send keeps failing.
+	queueMaxBackoff = 5 * time.Minute
+)
+
+// queueBaseline is kept in the state directory until the board is restored.
+type queueBaseline struct {
+	Model  string      `json:"model,omitempty"`
+	Layout [][]int     `json:"layout"`
+	Shown  *queue.Item `json:"shown,omitempty"`
+}
+
+// missedGrace is how late a firing may run, for example after the machine
+// slept, before it is skipped.
+const missedGrace = time.Minute
+
+func newDaemonCmd(stdout, stderr io.Writer, opts *options) *cobra.Command {
+	var (
+		path   string
+		queued bool
+		poll   time.Duration
+	)
+	daemonCmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Send scheduled or queued messages from one long-running process",
+		Long: `Send scheduled or queued messages from one long-running process.
+
+With --schedule, the daemon reads a schedule file listing messages and cron
+expressions and sends each entry when it fires, one at a time through a
+single client. When several entries fire in the same minute, only the
+highest-priority one is sent. SIGHUP reloads the file.
+
+With --queue, the daemon shows the highest-priority message added with
+enqueue that has not expired, and moves on to the next one when it expires
+or is dropped. When the queue empties, the message the board showed before
+the first item comes back.
+
+SIGINT and SIGTERM stop the daemon.`,
+		Args: exactArgsWithHelp(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if cmd.Flags().Changed("poll") && !queued {
+				return usageError(cmd, errors.New("--poll requires --queue"))
+			}
+			if queued {
+				if poll <= 0 {
+					return usageError(cmd, fmt.Errorf("invalid --poll %s (must be positive)", poll))
+				}
+				return runQueueDaemon(cmd, stdout, stderr, opts, poll)
+			}
+			return runDaemon(cmd, stdout, stderr, opts, path)
+		},
+	}
+	daemonCmd.Flags().StringVar(&path, "schedule", "", "Schedule file (YAML)")
+	daemonCmd.Flags().BoolVar(&queued, "queue", false, "Show messages from the queue filled by enqueue")
+	daemonCmd.Flags().DurationVar(&poll, "poll", 5*time.Second, "With --queue, how often to check the queue for changes")
+	addForceFlag(daemonCmd, opts)
+	daemonCmd.MarkFlagsOneRequired("schedule", "queue")
+	daemonCmd.MarkFlagsMutuallyExclusive("schedule", "queue")
+	return daemonCmd
+}
+
+func runDaemon(cmd *cobra.Command, stdout, stderr io.Writer, opts *options, path string) error {
+	sched, err := loadSchedule(path)
+	if err != nil {
+		return err
+	}
+	board, err := buildBoard(stderr, opts)
+	if err != nil {
+		return err
+	}
+	formatter, err := buildFormatter(stderr, opts, board)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	reload := make(chan os.Signal, 1)
+	opts.notifyReload(reload)
+	defer signal.Stop(reload)
+
+	after := opts.clock.Now()
+	for {
+		slot, ok := sched.Next(after)
+		var due <-chan time.Time
+		if ok {
+			due = opts.clock.After(slot.Time.Sub(opts.clock.Now()))
+		} else {
+			warn(stderr, errors.New("no schedule entry fires in the next few years; waiting for SIGHUP"))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-reload:
+			reloaded, err := loadSchedule(path)
+			if err != nil {
+				warn(stderr, fmt.Errorf("keeping the previous schedule: %w", err))
+				continue
+			}
+			sched = reloaded
+			after = opts.clock.Now()
+			_, _ = fmt.Fprintf(stderr, "reloaded %s (%d entries)\n", path, len(sched.Entries))
+		case <-due:
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err := fireSlot(ctx, stdout, stderr, opts, board, formatter, slot); err != nil {
+				return err
+			}
+			after = slot.Time
+		}
+	}
+}
+
+func fireSlot(ctx context.Context, stdout, stderr io.Writer, opts *options, board vestaboard.Board, formatter vestaboard.Formatter, slot schedule.Slot) error {
+	stamp := slot.Time.Format(time.RFC3339)
+	entry := slot.Entries[0]
+	for _, skipped := range slot.Entries[1:] {
+		if err := logFiring(stdout, stamp, skipped.Name, "skipped for "+entry.Name); err != nil {
+			return err
+		}
+	}
+	if late := opts.clock.Now().Sub(slot.Time); late > missedGrace {
+		return logFiring(stdout, stamp, entry.Name, fmt.Sprintf("missed by %s", late.Round(time.Second)))
+	}
+
+	if err := sendEntry(ctx, stderr, opts, board, formatter, entry); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		warn(stderr, fmt.Errorf("entry %q: %w", entry.Name, err))
+		return logFiring(stdout, stamp, entry.Name, sendResult(err))
+	}
+	return logFiring(stdout, stamp, entry.Name, historySent)
+}
+
+func sendEntry(ctx context.Context, stderr io.Writer, opts *options, board vestaboard.Board, formatter vestaboard.Formatter, entry *schedule.Entry) error {
+	input := entryInput(entry)
+	characters, model, err := entryStyle(entry).render(ctx, formatter, input)
+	if err != nil {
+		return err
+	}
+	if entry.Transition != nil {
+		transitionType, _ := resolveTransitionType(entry.Transition.Type)
+		transitionSpeed, _ := resolveTransitionSpeed(entry.Transition.Speed)
+		if err := board.SetTransition(ctx, transitionType, transitionSpeed); err != nil {
+			return fmt.Errorf("set transition: %w", err)
+		}
+	}
+	return deliver(ctx, stderr, opts, board, delivery{Command: "daemon", Source: input, Model: model, Characters: characters})
+}
+
+func runQueueDaemon(cmd *cobra.Command, stdout, stderr io.Writer, opts *options, poll time.Duration) error {
+	board, err := buildBoard(stderr, opts)
+	if err != nil {
+		return err
+	}
+	store, err := openState(opts)
+	if err != nil {
+		return err
+	}
+	q := queue.New(store)
+	// A daemon restarted while the queue was showing picks up where it
+	// left off instead of taking the queued item for the previous message.
+	var saved queueBaseline
+	if err := store.ReadJSON(queueBaselineFile, &saved); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var (
+		baseline *queueBaseline
+		// heldUntil is when a quiet hours window holding sends ends.
+		heldUntil time.Time
+		// failedID is the item whose last send failed, retried at retryAt.
+		failedID string
+		failures int
+		retryAt  time.Time
+	)
+	if saved.Layout != nil {
+		baseline = &saved
+	}
+	for {
+		now := opts.clock.Now()
+		items, err := q.Prune(ctx, now)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			warn(stderr, err)
+		}
+		stamp := now.Format(time.RFC3339)
+		switch {
+		case err != nil:
+		case now.Before(heldUntil):
+			// Expired items are still pruned while sends are held.
+		case len(items) > 0 && items[0].ID == failedID && now.Before(retryAt):
+		case len(items) > 0 && (baseline == nil || baseline.Shown == nil || items[0].ID != baseline.Shown.ID):
+			top := items[0]
+			if baseline == nil {
+				previous, err := board.GetCurrentState(ctx)
+				if err != nil {
+					if ctx.Err() != nil {
+						return nil
+					}
+					warn(stderr, fmt.Errorf("read the board before showing the queue: %w", err))
+					break
+				}
+				baseline = &queueBaseline{Model: previous.Model, Layout: previous.Layout}
+			}
+			result, err := showQueueItem(ctx, stderr, opts, board, top)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if until, quiet := quietUntil(err); quiet {
+				heldUntil = until
+			} else if err != nil {
+				if top.ID != failedID {
+					failedID, failures = top.ID, 0
+				}
+				failures++
+				retryAt = now.Add(watchBackoff(poll, queueMaxBackoff, failures))
+				warn(stderr, fmt.Errorf("item %s: %w (retrying at %s)", top.ID, err, retryAt.Format(displayTimeFormat)))
+			} else {
+				failedID, baseline.Shown = "", &top
+			}
+			if err := store.WriteJSON(queueBaselineFile, baseline); err != nil {
+				warn(stderr, err)
+			}
+			if err := logFiring(stdout, stamp, top.ID, result); err != nil {
+				return err
+			}
+		case len(items) == 0 && baseline != nil:
+			if baseline.Shown != nil {
+				result, err := restoreBaseline(ctx, stderr, opts, board, baseline)
+				if ctx.Err() != nil {
+					return nil
+				}
+				if err := logFiring(stdout, stamp, "previous", result); err != nil {
+					return err
+				}
+				if until, quiet := quietUntil(err); quiet {
+					// Try the restore again once the window ends.
+					heldUntil = until
+					break
+				}
+				if err != nil {
+					warn(stderr, fmt.Errorf("restore the board: %w", err))
+				}
+			}
+			baseline, failedID = nil, ""
+			if err := store.Remove(queueBaselineFile); err != nil {
+				warn(stderr, err)
+			}
+		}
+
+		now = opts.clock.Now()
+		wait := queueWait(items, now, poll)
+		for _, until := range []time.Time{heldUntil, retryAt} {
+			if d := until.Sub(now); d > 0 && d < wait {
+				wait = d
+			}
+		}
+		if err := sleepContext(ctx, opts.clock, wait); err != nil {
+			return nil
+		}
+	}
+}
+
+func showQueueItem(ctx context.Context, stderr io.Writer, opts *options, board vestaboard.Board, item queue.Item) (string, error) {
+	err := deliver(ctx, stderr, opts, board, delivery{Command: "daemon", Source: item.Source, Model: item.Model, Characters: item.Characters})
+	return sendResult(err), err
+}
+
+// restoreBaseline leaves the board alone when something other than the last
+// queued item is on it.
+func restoreBaseline(ctx context.Context, stderr io.Writer, opts *options, board vestaboard.Board, baseline *queueBaseline) (string, error) {
+	current, err := board.GetCurrentState(ctx)
+	if err != nil {
+		return historyFailed, err
+	}
+	if len(diffLayouts(current.Layout, baseline.Shown.Characters)) != 0 {
+		return "skipped (the board has changed)", nil
+	}
+	return showQueueItem(ctx, stderr, opts, board, queue.Item{Source: "previous", Model: baseline.Model, Characters: baseline.Layout})
+}
+
+func queueWait(items []queue.Item, now time.Time, poll time.Duration) time.Duration {
+	wait := poll
+	for _, item := range items {
+		if item.ExpiresAt.IsZero() {
+			continue
+		}
+		if until := item.ExpiresAt.Sub(now); until < wait {
+			wait = until
+		}
+	}
+	return wait
+}
+
+func logFiring(stdout io.Writer, stamp, name, result string) error {
+	if _, err := fmt.Fprintf(stdout, "%s %s %s\n", stamp, name, result); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func notifyHangup(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGHUP)
+}
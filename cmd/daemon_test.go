@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	vbapi "vbcli/internal/vestaboard"
+	"vbcli/vestaboard"
+)
+
+// stepClock is a vbapi.Clock driven by the test: every timer is handed
+// to the test, which decides when it fires.
+type stepClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits chan stepWait
+}
+
+type stepWait struct {
+	d    time.Duration
+	fire chan time.Time
+}
+
+func newStepClock(now time.Time) *stepClock {
+	return &stepClock{now: now, waits: make(chan stepWait)}
+}
+
+func (c *stepClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *stepClock) After(d time.Duration) <-chan time.Time {
+	w := stepWait{d: d, fire: make(chan time.Time, 1)}
+	c.waits <- w
+	return w.fire
+}
+
+// next returns the timer the command is waiting on.
+func (c *stepClock) next(t *testing.T) stepWait {
+	t.Helper()
+	select {
+	case w := <-c.waits:
+		return w
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the command to sleep")
+		return stepWait{}
+	}
+}
+
+// fire advances the clock to the timer's deadline and fires it.
+func (c *stepClock) fire(w stepWait) {
+	c.mu.Lock()
+	c.now = c.now.Add(w.d)
+	now := c.now
+	c.mu.Unlock()
+	w.fire <- now
+}
+
+// startRoot runs the root command in the background until the returned
+// stop function cancels it, and returns what it printed.
+func startRoot(t *testing.T, rootOptions []Option, args ...string) func() (string, error) {
+	t.Helper()
+	var stdout bytes.Buffer
+	rootOptions = append([]Option{WithStateDir(t.TempDir()), WithConfigFile(emptyConfig(t))}, rootOptions...)
+	root := NewRootCmd(strings.NewReader(""), &stdout, &bytes.Buffer{}, rootOptions...)
+	root.SetArgs(args)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- root.ExecuteContext(ctx) }()
+	return func() (string, error) {
+		cancel()
+		err := <-done
+		return stdout.String(), err
+	}
+}
+
+func writeScheduleFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write schedule: %v", err)
+	}
+}
+
+func TestDaemonSendsByPriorityAndReloads(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "schedule.yaml")
+	writeScheduleFile(t, path, `
+timezone: UTC
+entries:
+  - name: often
+    cron: "*/5 * * * *"
+    characters: [[1]]
+    priority: 1
+  - name: hourly
+    cron: "@hourly"
+    characters: [[2]]
+    priority: 2
+    transition: {type: wave, speed: fast}
+`)
+	fake := vestaboard.NewFakeBoard(nil)
+	clock := newStepClock(time.Date(2026, 10, 16, 9, 58, 30, 0, time.UTC))
+	reloads := make(chan chan<- os.Signal, 1)
+	notify := Option(func(o *options) {
+		o.notifyReload = func(c chan<- os.Signal) { reloads <- c }
+	})
+	stop := startRoot(t, []Option{WithBoard(fake), WithClock(clock), notify}, "daemon", "--schedule", path)
+
+	w := clock.next(t)
+	if w.d != 90*time.Second {
+		t.Fatalf("first wait %s, want 1m30s", w.d)
+	}
+	clock.fire(w)
+
+	if w = clock.next(t); w.d != 5*time.Minute {
+		t.Fatalf("second wait %s, want 5m", w.d)
+	}
+	writeScheduleFile(t, path, "timezone: UTC\nentries:\n  - name: minutely\n    cron: '* * * * *'\n    characters: [[3]]\n")
+	(<-reloads) <- syscall.SIGHUP
+
+	if w = clock.next(t); w.d != time.Minute {
+		t.Fatalf("wait after reload %s, want 1m", w.d)
+	}
+	clock.fire(w)
+	clock.next(t)
+
+	out, err := stop()
+	if err != nil {
+		t.Fatalf("daemon: %v", err)
+	}
+	want := "2026-10-16T10:00:00Z often skipped for hourly\n" +
+		"2026-10-16T10:00:00Z hourly sent\n" +
+		"2026-10-16T10:01:00Z minutely sent\n"
+	if out != want {
+		t.Fatalf("output:\n%s\nwant:\n%s", out, want)
+	}
+	if want := [][][]int{{{2}}, {{3}}}; !reflect.DeepEqual(fake.Sent, want) {
+		t.Fatalf("sent %v, want %v", fake.Sent, want)
+	}
+	body, _ := fake.GetTransition(context.Background())
+	if !strings.Contains(string(body), `"wave"`) {
+		t.Fatalf("transition not set: %s", body)
+	}
+}
+
+func TestDaemonLogsFailedSendsAndKeepsRunning(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "schedule.yaml")
+	writeScheduleFile(t, path, "timezone: UTC\nentries:\n  - name: tick\n    cron: '* * * * *'\n    characters: [[1]]\n")
+	fake := vestaboard.NewFakeBoard(nil)
+	fake.SendErr = &vbapi.APIError{Category: vbapi.CategoryServer, StatusCode: 503}
+	clock := newStepClock(time.Date(2026, 10, 16, 9, 59, 0, 0, time.UTC))
+	stop := startRoot(t, []Option{WithBoard(fake), WithClock(clock)}, "daemon", "--schedule", path)
+
+	clock.fire(clock.next(t))
+	clock.next(t)
+	out, err := stop()
+	if err != nil {
+		t.Fatalf("daemon: %v", err)
+	}
+	if out != "2026-10-16T10:00:00Z tick failed\n" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+}
+
+func TestDaemonRejectsInvalidSchedule(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tests := map[string]string{
+		"model":      "entries:\n  - cron: '* * * * *'\n    message: hi\n    model: wall\n",
+		"transition": "entries:\n  - cron: '* * * * *'\n    message: hi\n    transition: {type: spin, speed: fast}\n",
+		"characters": "entries:\n  - cron: '* * * * *'\n    characters: [[1]]\n    model: wall\n",
+		"align":      "entries:\n  - cron: '* * * * *'\n    message: hi\n    align: middle\n",
+	}
+	for name, content := range tests {
+		path := filepath.Join(dir, name+".yaml")
+		writeScheduleFile(t, path, content)
+		_, err := runRoot(t, "", []Option{WithBoard(vestaboard.NewFakeBoard(nil))}, "daemon", "--schedule", path)
+		if err == nil || !strings.Contains(err.Error(), path) {
+			t.Fatalf("%s: expected error naming the file, got %v", name, err)
+		}
+	}
+}
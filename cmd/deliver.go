@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"vbcli/internal/state"
+	vbapi "vbcli/internal/vestaboard"
+	"vbcli/vestaboard"
+)
+
+const (
+	lastSentFile  = "last-sent.json"
+	sendLimitFile = "send-limit.json"
+	historyFile   = "history.jsonl"
+	// historyMaxBytes and historyKeep bound the journal to about 4 MiB.
+	historyMaxBytes = 1 << 20
+	historyKeep     = 3
+
+	historySent    = "sent"
+	historyFailed  = "failed"
+	historyBlocked = "blocked"
+)
+
+type lastSent struct {
+	Layout [][]int   `json:"layout"`
+	SentAt time.Time `json:"sentAt"`
+}
+
+type delivery struct {
+	Command    string
+	Source     string
+	Model      string
+	Characters [][]int
+}
+
+type historyEntry struct {
+	Time       time.Time `json:"time"`
+	Command    string    `json:"command"`
+	Source     string    `json:"source,omitempty"`
+	Characters [][]int   `json:"characters"`
+	Model      string    `json:"model,omitempty"`
+	Profile    string    `json:"profile"`
+	Result     string    `json:"result"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// deliver applies the quiet hours policy, sends, and records the send in
+// the history and the last-sent cache. Every command that changes the board
+// goes through it.
+func deliver(ctx context.Context, stderr io.Writer, opts *options, board vestaboard.Board, d delivery) error {
+	characters, sendErr := applyPolicy(stderr, opts, d.Characters)
+	if _, deferred := deferredUntil(sendErr); deferred {
+		return sendErr
+	}
+	if sendErr != nil && !errors.Is(sendErr, ErrQuietHours) {
+		return sendErr
+	}
+	if sendErr == nil {
+		sendErr = board.SendCharacters(ctx, characters)
+	}
+
+	entry := historyEntry{
+		Time:       opts.clock.Now().UTC(),
+		Command:    d.Command,
+		Source:     d.Source,
+		Characters: characters,
+		Model:      d.Model,
+		Profile:    boardProfile(opts),
+		Result:     historySent,
+	}
+	if entry.Model == "" {
+		entry.Model = vestaboard.InferModel(vestaboard.Dimensions(d.Characters))
+	}
+	switch {
+	case errors.Is(sendErr, ErrQuietHours):
+		entry.Result, entry.Error = historyBlocked, sendErr.Error()
+	case sendErr != nil:
+		entry.Result, entry.Error = historyFailed, sendErr.Error()
+	}
+	// Record the outcome even when the caller's context was cancelled
+	// mid-send.
+	recordCtx := context.WithoutCancel(ctx)
+	if err := appendHistory(recordCtx, opts, entry); err != nil {
+		warn(stderr, err)
+	}
+	if sendErr != nil {
+		return sendErr
+	}
+	if err := rememberSent(recordCtx, opts, characters); err != nil {
+		warn(stderr, err)
+	}
+	return nil
+}
+
+// deliverWaiting is deliver for one-shot sends, which wait out a quiet
+// hours queue window and try again.
+func deliverWaiting(ctx context.Context, stderr io.Writer, opts *options, board vestaboard.Board, d delivery) error {
+	for {
+		err := deliver(ctx, stderr, opts, board, d)
+		var quiet *quietHoursError
+		until, deferred := deferredUntil(err)
+		if !deferred || !errors.As(err, &quiet) {
+			return err
+		}
+		_, _ = fmt.Fprintf(stderr, "quiet hours (%s): waiting until %s to send\n", quiet.Decision.Window.Name, until.Format(displayTimeFormat))
+		if err := sleepContext(ctx, opts.clock, until.Sub(opts.clock.Now())); err != nil {
+			return err
+		}
+	}
+}
+
+func historyJournal(opts *options) (*state.Journal, error) {
+	store, err := openState(opts)
+	if err != nil {
+		return nil, err
+	}
+	return store.Journal(historyFile, historyMaxBytes, historyKeep), nil
+}
+
+func appendHistory(ctx context.Context, opts *options, entry historyEntry) error {
+	journal, err := historyJournal(opts)
+	if err != nil {
+		return err
+	}
+	return journal.Append(ctx, entry)
+}
+
+func readHistory(opts *options) ([]historyEntry, error) {
+	journal, err := historyJournal(opts)
+	if err != nil {
+		return nil, err
+	}
+	var entries []historyEntry
+	err = journal.Read(func(line []byte) error {
+		var entry historyEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries, err
+}
+
+func rememberSent(ctx context.Context, opts *options, characters [][]int) error {
+	store, err := openState(opts)
+	if err != nil {
+		return err
+	}
+	unlock, err := store.Lock(ctx, lastSentFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cache := map[string]lastSent{}
+	if err := store.ReadJSON(lastSentFile, &cache); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	cache[boardProfile(opts)] = lastSent{Layout: characters, SentAt: opts.clock.Now().UTC()}
+	return store.WriteJSON(lastSentFile, cache)
+}
+
+func cachedLayout(opts *options) ([][]int, bool, error) {
+	store, err := openState(opts)
+	if err != nil {
+		return nil, false, err
+	}
+	cache := map[string]lastSent{}
+	if err := store.ReadJSON(lastSentFile, &cache); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	entry, ok := cache[boardProfile(opts)]
+	return entry.Layout, ok, nil
+}
+
+func openState(opts *options) (*state.Store, error) {
+	dir := opts.stateDir
+	if dir == "" {
+		var err error
+		dir, err = state.DefaultDir(os.Getenv)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return state.Open(dir)
+}
+
+// boardProfile keeps cached state from one board apart from another. Cloud
+// boards are told apart by a fingerprint of their token.
+func boardProfile(opts *options) string {
+	if opts.board != nil {
+		return "custom"
+	}
+	backend, err := resolveBackend(opts.backend)
+	if err != nil {
+		return "unknown"
+	}
+	if backend == backendLocal {
+		return "local:" + resolveSetting(opts.host, envHost)
+	}
+	sum := sha256.Sum256([]byte(strings.TrimSpace(os.Getenv(envVestaboardToken))))
+	profile := "cloud:" + hex.EncodeToString(sum[:6])
+	if apiURL := resolveSetting(opts.apiURL, envAPIURL); apiURL != "" {
+		profile += "@" + apiURL
+	}
+	return profile
+}
+
+func warn(stderr io.Writer, err error) {
+	_, _ = fmt.Fprintf(stderr, "warning: %v\n", err)
+}
+
+const (
+	ifChangedLive  = "live"
+	ifChangedCache = "cache"
+
+	sendStatusSent      = "sent"
+	sendStatusUnchanged = "unchanged"
+)
+
+func resolveIfChanged(value string) (string, error) {
+	v := strings.ToLower(strings.TrimSpace(value))
+	switch v {
+	case ifChangedLive, ifChangedCache:
+		return v, nil
+	default:
+		return "", fmt.Errorf("invalid --if-changed %q (expected \"live\" or \"cache\")", value)
+	}
+}
+
+func boardShows(ctx context.Context, opts *options, board vestaboard.Board, characters [][]int) (bool, error) {
+	if opts.ifChanged == ifChangedCache {
+		layout, ok, err := cachedLayout(opts)
+		if err != nil || !ok {
+			return false, err
+		}
+		return len(diffLayouts(layout, characters)) == 0, nil
+	}
+	current, err := board.GetCurrentState(ctx)
+	if err != nil {
+		return false, err
+	}
+	return len(diffLayouts(current.Layout, characters)) == 0, nil
+}
+
+func writeSendStatus(stdout io.Writer, status string) error {
+	if _, err := fmt.Fprintln(stdout, status); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+// sharedSendLimit keeps the --min-interval bucket in the state directory,
+// so every vbcli process sending to the board shares it.
+type sharedSendLimit struct {
+	store   *state.Store
+	profile string
+}
+
+func (s *sharedSendLimit) Lock(ctx context.Context) (*vbapi.Bucket, func() error, error) {
+	unlock, err := s.store.Lock(ctx, sendLimitFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	buckets := map[string]*vbapi.Bucket{}
+	if err := s.store.ReadJSON(sendLimitFile, &buckets); err != nil && !errors.Is(err, os.ErrNotExist) {
+		unlock()
+		return nil, nil, err
+	}
+	bucket := buckets[s.profile]
+	if bucket == nil {
+		bucket = &vbapi.Bucket{}
+		buckets[s.profile] = bucket
+	}
+	return bucket, func() error {
+		defer unlock()
+		return s.store.WriteJSON(sendLimitFile, buckets)
+	}, nil
+}
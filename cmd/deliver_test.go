@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"vbcli/vestaboard"
+)
+
+func TestSendIfChangedLive(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{8, 9}})
+	formatter := &stubFormatter{characters: [][]int{{8, 9}}}
+	rootOptions := []Option{WithBoard(fake), WithFormatter(formatter)}
+
+	out, err := runRoot(t, "", rootOptions, "send", "--if-changed", "hi")
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if out != "unchanged\n" || len(fake.Sent) != 0 {
+		t.Fatalf("expected no send, got %q and %d sends", out, len(fake.Sent))
+	}
+
+	formatter.characters = [][]int{{1, 2}}
+	out, err = runRoot(t, "", rootOptions, "send", "--if-changed", "ab")
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if out != "sent\n" || len(fake.Sent) != 1 {
+		t.Fatalf("expected a send, got %q and %d sends", out, len(fake.Sent))
+	}
+}
+
+func TestSendIfChangedCache(t *testing.T) {
+	t.Parallel()
+
+	// The fake board starts with something else on it, so only the cache
+	// can tell that the message is already showing.
+	fake := vestaboard.NewFakeBoard([][]int{{0, 0}})
+	rootOptions := []Option{WithBoard(fake), WithStateDir(t.TempDir())}
+
+	out, err := runRoot(t, "", rootOptions, "send", "--if-changed=cache", "[[1,2]]")
+	if err != nil || out != "sent\n" {
+		t.Fatalf("first send: %q %v", out, err)
+	}
+	fake.Sent = nil
+	out, err = runRoot(t, "", rootOptions, "send", "--if-changed=cache", "[[1,2]]")
+	if err != nil || out != "unchanged\n" || len(fake.Sent) != 0 {
+		t.Fatalf("second send: %q %v, %d sends", out, err, len(fake.Sent))
+	}
+
+	// send-raw and clear update the cache too.
+	if _, err := runRoot(t, "", rootOptions, "send-raw", "[[3]]"); err != nil {
+		t.Fatalf("send-raw: %v", err)
+	}
+	out, err = runRoot(t, "", rootOptions, "send", "--if-changed=cache", "[[1,2]]")
+	if err != nil || out != "sent\n" {
+		t.Fatalf("after send-raw: %q %v", out, err)
+	}
+}
+
+func TestSendIfChangedRejectsBadValues(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard(nil)
+	for _, args := range [][]string{
+		{"send", "--if-changed=sometimes", "[[1]]"},
+		{"send", "--format", "--if-changed", "[[1]]"},
+	} {
+		if _, err := runRoot(t, "", []Option{WithBoard(fake)}, args...); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+}
+
+func TestBoardProfile(t *testing.T) {
+	t.Setenv(envVestaboardToken, "token-a")
+	t.Setenv(envAPIURL, "")
+	t.Setenv(envBackend, "")
+	t.Setenv(envHost, "")
+
+	cloudA := boardProfile(&options{})
+	t.Setenv(envVestaboardToken, "token-b")
+	cloudB := boardProfile(&options{})
+	local := boardProfile(&options{backend: "local", host: "10.0.0.5"})
+
+	if cloudA == cloudB {
+		t.Fatal("different tokens must give different profiles")
+	}
+	if local != "local:10.0.0.5" {
+		t.Fatalf("local profile = %q", local)
+	}
+	if boardProfile(&options{apiURL: "http://proxy"}) != cloudB+"@http://proxy" {
+		t.Fatalf("api URL not part of profile: %q", boardProfile(&options{apiURL: "http://proxy"}))
+	}
+}
+
+func TestMinIntervalAppliesAcrossProcesses(t *testing.T) {
+	t.Setenv(envVestaboardToken, "abc123")
+	t.Setenv(envBackend, "")
+
+	var sends atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		sends.Add(1)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	// Each runRoot builds its own client, as a separate vbcli process would.
+	rootOptions := []Option{WithStateDir(t.TempDir())}
+	args := []string{"--api-url", server.URL, "--min-interval", "1h", "--fail-fast", "send-raw", "[[1]]"}
+	if _, err := runRoot(t, "", rootOptions, args...); err != nil {
+		t.Fatalf("first send: %v", err)
+	}
+	_, err := runRoot(t, "", rootOptions, args...)
+	if ExitCode(err) != ExitRateLimited {
+		t.Fatalf("second send: got %v, want a rate limit error", err)
+	}
+	if sends.Load() != 1 {
+		t.Fatalf("board got %d sends, want 1", sends.Load())
+	}
+}
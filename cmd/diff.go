@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"vbcli/internal/codec"
+	"vbcli/internal/flap"
+	"vbcli/internal/preview"
+	"vbcli/vestaboard"
+)
+
+type cellChange struct {
+	Row    int `json:"row"`
+	Column int `json:"column"`
+	From   int `json:"from"`
+	To     int `json:"to"`
+}
+
+type diffResult struct {
+	Changed bool         `json:"changed"`
+	Current string       `json:"currentId,omitempty"`
+	Cells   []cellChange `json:"cells"`
+}
+
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func newDiffCmd(stdin io.Reader, stdout, stderr io.Writer, opts *options) *cobra.Command {
+	var jsonOutput bool
+	diffCmd := &cobra.Command{
+		Use:   "diff [message|characters-json|-]",
+		Short: "Show which cells a message would change on the board",
+		Long: `Show which cells a message would change on the board.
+
+Exits 0 when the board already shows the message and 2 when it would change.`,
+		Args: maxArgsWithHelp(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiff(cmd, stdin, stdout, stderr, opts, args, jsonOutput)
+		},
+	}
+	diffCmd.Flags().BoolVar(&jsonOutput, "json", false, "Print changed cells as JSON")
+	diffCmd.Flags().StringVarP(&opts.model, flagModel, "m", "", "VBML model: flagship or note (default: the board's model)")
+	diffCmd.Flags().StringVarP(&opts.align, "align", "a", "center", "VBML align: top, center, or bottom")
+	diffCmd.Flags().StringVarP(&opts.justify, "justify", "j", "center", "VBML justify: left, center, right, or justified")
+	return diffCmd
+}
+
+func runDiff(cmd *cobra.Command, stdin io.Reader, stdout, stderr io.Writer, opts *options, args []string, jsonOutput bool) error {
+	resolved, err := resolveCommandInput(cmd, stdin, args, "message")
+	if err != nil {
+		return err
+	}
+	board, err := buildBoard(stderr, opts)
+	if err != nil {
+		return err
+	}
+	state, err := board.GetCurrentState(cmd.Context())
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(opts.model) == "" && os.Getenv(envVestaboardModel) == "" {
+		opts.model = state.Model
+	}
+
+	formatter, err := buildFormatter(stderr, opts, board)
+	if err != nil {
+		return err
+	}
+	proposed, model, err := renderInput(cmd, formatter, opts, resolved)
+	if err != nil {
+		return err
+	}
+
+	result := diffResult{Current: state.ID, Cells: diffLayouts(state.Layout, proposed)}
+	result.Changed = len(result.Cells) > 0
+	if jsonOutput {
+		out, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("encode diff: %w", err)
+		}
+		if _, err := fmt.Fprintln(stdout, string(out)); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+	} else if err := writeSideBySide(stdout, opts, state.Layout, proposed, model, result.Cells); err != nil {
+		return err
+	}
+
+	if result.Changed {
+		return ErrChanged
+	}
+	return nil
+}
+
+// diffLayouts treats missing cells as blank.
+func diffLayouts(current, proposed [][]int) []cellChange {
+	currentRows, currentColumns := vestaboard.Dimensions(current)
+	proposedRows, proposedColumns := vestaboard.Dimensions(proposed)
+	rows, columns := max(currentRows, proposedRows), max(currentColumns, proposedColumns)
+	current, proposed = flap.Pad(current, rows, columns), flap.Pad(proposed, rows, columns)
+
+	changes := []cellChange{}
+	for r := 0; r < rows; r++ {
+		for c := 0; c < columns; c++ {
+			if current[r][c] != proposed[r][c] {
+				changes = append(changes, cellChange{Row: r, Column: c, From: current[r][c], To: proposed[r][c]})
+			}
+		}
+	}
+	return changes
+}
+
+func writeSideBySide(stdout io.Writer, opts *options, current, proposed [][]int, model string, changes []cellChange) error {
+	mode, err := preview.ParseMode(opts.color, stdout)
+	if err != nil {
+		return err
+	}
+	changed := make(map[[2]int]bool, len(changes))
+	for _, change := range changes {
+		changed[[2]int{change.Row, change.Column}] = true
+	}
+	previewOpts := preview.Options{
+		Mode:      mode,
+		Codec:     codec.ForModel(model),
+		Highlight: func(row, column int) bool { return changed[[2]int{row, column}] },
+	}
+
+	rows, columns := vestaboard.Dimensions(proposed)
+	currentRows, currentColumns := vestaboard.Dimensions(current)
+	rows, columns = max(rows, currentRows), max(columns, currentColumns)
+	var left, right bytes.Buffer
+	if err := preview.Render(&left, flap.Pad(current, rows, columns), previewOpts); err != nil {
+		return err
+	}
+	if err := preview.Render(&right, flap.Pad(proposed, rows, columns), previewOpts); err != nil {
+		return err
+	}
+	leftLines := strings.Split(strings.TrimSuffix(left.String(), "\n"), "\n")
+	rightLines := strings.Split(strings.TrimSuffix(right.String(), "\n"), "\n")
+	width := visibleWidth(leftLines[0])
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "%-*s   %s\n", width, "current", "proposed")
+	for i := range leftLines {
+		fmt.Fprintf(&out, "%s   %s\n", leftLines[i], rightLines[i])
+	}
+	switch len(changes) {
+	case 0:
+		out.WriteString("no cells would change\n")
+	case 1:
+		out.WriteString("1 cell would change\n")
+	default:
+		fmt.Fprintf(&out, "%d cells would change\n", len(changes))
+	}
+	if _, err := io.WriteString(stdout, out.String()); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func visibleWidth(line string) int {
+	return utf8.RuneCountInString(ansiEscape.ReplaceAllString(line, ""))
+}
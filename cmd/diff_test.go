@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"vbcli/vestaboard"
+)
+
+func TestDiffSideBySide(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{1, 2}})
+	out, err := runRoot(t, "", []Option{WithBoard(fake)}, "--color", "none", "diff", "[[1,3]]")
+	if !errors.Is(err, ErrChanged) || ExitCode(err) != ExitChanged {
+		t.Fatalf("expected ErrChanged, got %v", err)
+	}
+	want := "current    proposed\n" +
+		"+------+   +------+\n" +
+		"| A [B]|   | A [C]|\n" +
+		"+------+   +------+\n" +
+		"1 cell would change\n"
+	if out != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", out, want)
+	}
+	if len(fake.Sent) != 0 {
+		t.Fatal("diff must not send")
+	}
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{1, 2}})
+	out, err := runRoot(t, "", []Option{WithBoard(fake)}, "diff", "--json", "[[1,2]]")
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	var result diffResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if result.Changed || len(result.Cells) != 0 || result.Current != "fake-0" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+func TestDiffJSONListsChangedCells(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{1, 2}})
+	out, err := runRoot(t, "", []Option{WithBoard(fake)}, "diff", "--json", "[[0,2],[5]]")
+	if !errors.Is(err, ErrChanged) {
+		t.Fatalf("expected ErrChanged, got %v", err)
+	}
+	var result diffResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	want := []cellChange{{Row: 0, Column: 0, From: 1, To: 0}, {Row: 1, Column: 0, From: 0, To: 5}}
+	if !result.Changed || !reflect.DeepEqual(result.Cells, want) {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+func TestDiffUsesBoardModelForTemplates(t *testing.T) {
+	t.Setenv(envVestaboardModel, "")
+
+	note := make([][]int, 3)
+	for i := range note {
+		note[i] = make([]int, 15)
+	}
+	fake := vestaboard.NewFakeBoard(note)
+	out, err := runRoot(t, "", []Option{WithBoard(fake)}, "--renderer", "local", "diff", "--json", "hi")
+	if !errors.Is(err, ErrChanged) {
+		t.Fatalf("expected ErrChanged, got %v", err)
+	}
+	var result diffResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	// On a note the message is centred on the middle of three rows.
+	if len(result.Cells) != 2 || result.Cells[0].Row != 1 || result.Cells[1].Row != 1 {
+		t.Fatalf("unexpected cells: %+v", result.Cells)
+	}
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	vbapi "vbcli/internal/vestaboard"
+)
+
+// Process exit codes. Codes 5-7 indicate failures that are worth retrying
+// later; the others need the command or configuration fixed first.
+const (
+	ExitFailure      = 1
+	ExitChanged      = 2
+	ExitAuth         = 3
+	ExitInvalidInput = 4
+	ExitRateLimited  = 5
+	ExitServer       = 6
+	ExitNetwork      = 7
+	ExitConflict     = 8
+	ExitQuietHours   = 9
+)
+
+// ErrChanged is returned by diff when the board would change. It reports a
+// result rather than a failure, so it is not printed as an error.
+var ErrChanged = errors.New("board would change")
+
+// ErrQuietHours is wrapped by errors from sends that the quiet hours policy
+// blocks.
+var ErrQuietHours = errors.New("quiet hours")
+
+// ErrNotAllowed is returned by policy check when a send would not go
+// through unchanged. Like ErrChanged it reports a result, so it is not
+// printed as an error.
+var ErrNotAllowed = fmt.Errorf("send not allowed: %w", ErrQuietHours)
+
+// ExitCode maps an error returned by the root command to a process exit code.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	if errors.Is(err, ErrChanged) {
+		return ExitChanged
+	}
+	if errors.Is(err, ErrQuietHours) {
+		return ExitQuietHours
+	}
+
+	var rateErr *vbapi.RateLimitError
+	if errors.As(err, &rateErr) {
+		return ExitRateLimited
+	}
+
+	var apiErr *vbapi.APIError
+	if !errors.As(err, &apiErr) {
+		return ExitFailure
+	}
+	switch apiErr.Category {
+	case vbapi.CategoryAuth:
+		return ExitAuth
+	case vbapi.CategoryInvalidInput:
+		return ExitInvalidInput
+	case vbapi.CategoryRateLimited:
+		return ExitRateLimited
+	case vbapi.CategoryServer:
+		return ExitServer
+	case vbapi.CategoryNetwork:
+		return ExitNetwork
+	case vbapi.CategoryConflict:
+		return ExitConflict
+	default:
+		return ExitFailure
+	}
+}
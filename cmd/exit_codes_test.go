@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	vbapi "vbcli/internal/vestaboard"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: 0},
+		{name: "plain", err: errors.New("boom"), want: ExitFailure},
+		{name: "auth", err: &vbapi.APIError{Category: vbapi.CategoryAuth}, want: ExitAuth},
+		{name: "rate limited", err: &vbapi.APIError{Category: vbapi.CategoryRateLimited}, want: ExitRateLimited},
+		{name: "wrapped server", err: fmt.Errorf("send: %w", &vbapi.APIError{Category: vbapi.CategoryServer}), want: ExitServer},
+		{name: "network", err: &vbapi.APIError{Category: vbapi.CategoryNetwork}, want: ExitNetwork},
+		{name: "client rate limit", err: &vbapi.RateLimitError{}, want: ExitRateLimited},
+		{name: "invalid input", err: &vbapi.APIError{Category: vbapi.CategoryInvalidInput}, want: ExitInvalidInput},
+		{name: "changed", err: ErrChanged, want: ExitChanged},
+		{name: "quiet hours", err: fmt.Errorf("%w: block", ErrQuietHours), want: ExitQuietHours},
+		{name: "not allowed", err: ErrNotAllowed, want: ExitQuietHours},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ExitCode(tc.err); got != tc.want {
+				t.Fatalf("got %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vbcli/internal/codec"
+)
+
+const historyUndo = "undo"
+
+type historyOptions struct {
+	limit   int
+	since   string
+	command string
+	failed  bool
+	all     bool
+	json    bool
+}
+
+func newHistoryCmd(stdout io.Writer, opts *options) *cobra.Command {
+	var historyOpts historyOptions
+	historyCmd := &cobra.Command{
+		Use:   "history",
+		Short: "List messages vbcli has sent, newest first",
+		Args:  exactArgsWithHelp(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runHistory(cmd, stdout, opts, historyOpts)
+		},
+	}
+	historyCmd.Flags().IntVarP(&historyOpts.limit, "limit", "n", 20, "Show at most this many entries (0 for all)")
+	historyCmd.Flags().StringVar(&historyOpts.since, "since", "", "Only entries newer than a duration ago (24h) or a time (2026-01-02T15:04:05Z)")
+	historyCmd.Flags().StringVar(&historyOpts.command, "command", "", "Only entries from this command, such as send or clear")
+	historyCmd.Flags().BoolVar(&historyOpts.failed, "failed", false, "Only sends that failed")
+	historyCmd.Flags().BoolVar(&historyOpts.all, "all-boards", false, "Include sends to other boards and backends")
+	historyCmd.Flags().BoolVar(&historyOpts.json, "json", false, "Print entries as a JSON array")
+	return historyCmd
+}
+
+func newUndoCmd(stdout, stderr io.Writer, opts *options) *cobra.Command {
+	var dryRun bool
+	undoCmd := &cobra.Command{
+		Use:   "undo",
+		Short: "Send the message that was on the board before the last send",
+		Long: `Send the message that was on the board before the last send.
+
+Repeating undo steps further back through the history of this board.`,
+		Args: exactArgsWithHelp(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runUndo(cmd, stdout, stderr, opts, dryRun)
+		},
+	}
+	undoCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the message undo would send without sending it")
+	addForceFlag(undoCmd, opts)
+	return undoCmd
+}
+
+func runHistory(cmd *cobra.Command, stdout io.Writer, opts *options, historyOpts historyOptions) error {
+	if historyOpts.limit < 0 {
+		return usageError(cmd, fmt.Errorf("invalid --limit %d", historyOpts.limit))
+	}
+	var since time.Time
+	if historyOpts.since != "" {
+		parsed, err := parseSince(historyOpts.since, opts.clock.Now())
+		if err != nil {
+			return usageError(cmd, err)
+		}
+		since = parsed
+	}
+
+	entries, err := readHistory(opts)
+	if err != nil {
+		return err
+	}
+	profile := boardProfile(opts)
+	selected := []historyEntry{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		switch {
+		case !historyOpts.all && entry.Profile != profile:
+		case !since.IsZero() && entry.Time.Before(since):
+		case historyOpts.command != "" && entry.Command != historyOpts.command:
+		case historyOpts.failed && entry.Result != historyFailed:
+		default:
+			selected = append(selected, entry)
+		}
+		if historyOpts.limit > 0 && len(selected) == historyOpts.limit {
+			break
+		}
+	}
+
+	if historyOpts.json {
+		out, err := json.Marshal(selected)
+		if err != nil {
+			return fmt.Errorf("encode history: %w", err)
+		}
+		if _, err := fmt.Fprintln(stdout, string(out)); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tCOMMAND\tRESULT\tMESSAGE")
+	for _, entry := range selected {
+		message := summarizeLayout(entry.Characters, entry.Model)
+		if entry.Result == historyFailed {
+			message = entry.Error
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.Time.Local().Format(time.DateTime), entry.Command, entry.Result, message)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func runUndo(cmd *cobra.Command, stdout, stderr io.Writer, opts *options, dryRun bool) error {
+	entries, err := readHistory(opts)
+	if err != nil {
+		return err
+	}
+	target, ok := undoTarget(entries, boardProfile(opts))
+	if !ok {
+		return errors.New("nothing to undo: the history has no earlier message for this board")
+	}
+	if dryRun {
+		if _, err := fmt.Fprintln(stdout, codec.ForModel(target.Model).Decode(target.Characters)); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+		return nil
+	}
+
+	board, err := buildBoard(stderr, opts)
+	if err != nil {
+		return err
+	}
+	return deliver(cmd.Context(), stderr, opts, board, delivery{
+		Command:    historyUndo,
+		Source:     target.Source,
+		Model:      target.Model,
+		Characters: target.Characters,
+	})
+}
+
+// undoTarget replays the sends to one board as a stack: a send pushes, an
+// undo pops. The entry below the top is what undo restores.
+func undoTarget(entries []historyEntry, profile string) (historyEntry, bool) {
+	var stack []historyEntry
+	for _, entry := range entries {
+		if entry.Profile != profile || entry.Result != historySent {
+			continue
+		}
+		if entry.Command == historyUndo {
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+		stack = append(stack, entry)
+	}
+	if len(stack) < 2 {
+		return historyEntry{}, false
+	}
+	return stack[len(stack)-2], true
+}
+
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q (expected a duration such as 24h or an RFC 3339 time)", value)
+}
+
+func summarizeLayout(layout [][]int, model string) string {
+	return summarizeText(codec.ForModel(model).Decode(layout))
+}
+
+func summarizeText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if len([]rune(text)) > 40 {
+		text = string([]rune(text)[:37]) + "..."
+	}
+	return text
+}
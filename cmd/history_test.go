@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"vbcli/vestaboard"
+)
+
+func TestHistoryRecordsSends(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard(nil)
+	clock := newInstantClock(time.Date(2026, 5, 4, 12, 0, 0, 0, time.UTC))
+	formatter := &stubFormatter{characters: [][]int{{8, 9}}}
+	rootOptions := []Option{WithBoard(fake), WithFormatter(formatter), WithClock(clock), WithStateDir(t.TempDir())}
+
+	if _, err := runRoot(t, "", rootOptions, "send", "hi"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if _, err := runRoot(t, "", rootOptions, "send-raw", "[[1]]"); err != nil {
+		t.Fatalf("send-raw: %v", err)
+	}
+	fake.SendErr = errors.New("board offline")
+	if _, err := runRoot(t, "", rootOptions, "clear"); err == nil {
+		t.Fatal("expected clear to fail")
+	}
+
+	out, err := runRoot(t, "", rootOptions, "history", "--json")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	var entries []historyEntry
+	if err := json.Unmarshal([]byte(out), &entries); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries: %+v", len(entries), entries)
+	}
+	if entries[0].Command != "clear" || entries[0].Result != historyFailed || entries[0].Error != "board offline" {
+		t.Fatalf("unexpected newest entry: %+v", entries[0])
+	}
+	if entries[1].Command != "send-raw" || entries[1].Source != "[[1]]" || entries[1].Model != "" {
+		t.Fatalf("unexpected send-raw entry: %+v", entries[1])
+	}
+	send := entries[2]
+	if send.Command != "send" || send.Source != "hi" || send.Model != "flagship" || send.Profile != "custom" ||
+		!reflect.DeepEqual(send.Characters, [][]int{{8, 9}}) || !send.Time.Equal(clock.Now()) {
+		t.Fatalf("unexpected send entry: %+v", send)
+	}
+
+	out, err = runRoot(t, "", rootOptions, "history", "--json", "--failed")
+	if err != nil || !strings.Contains(out, `"clear"`) || strings.Contains(out, `"send"`) {
+		t.Fatalf("history --failed: %q %v", out, err)
+	}
+	out, err = runRoot(t, "", rootOptions, "history", "-n", "1")
+	if err != nil || !strings.HasPrefix(out, "TIME") || strings.Count(out, "\n") != 2 || !strings.Contains(out, "board offline") {
+		t.Fatalf("history table: %q %v", out, err)
+	}
+}
+
+func TestUndoStepsBack(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard(nil)
+	rootOptions := []Option{WithBoard(fake), WithStateDir(t.TempDir())}
+	for _, layout := range []string{"[[1]]", "[[2]]", "[[3]]"} {
+		if _, err := runRoot(t, "", rootOptions, "send-raw", layout); err != nil {
+			t.Fatalf("send-raw: %v", err)
+		}
+	}
+
+	if out, err := runRoot(t, "", rootOptions, "undo", "--dry-run"); err != nil || out != "B\n" {
+		t.Fatalf("undo --dry-run: %q %v", out, err)
+	}
+	for _, want := range []int{2, 1} {
+		if _, err := runRoot(t, "", rootOptions, "undo"); err != nil {
+			t.Fatalf("undo: %v", err)
+		}
+		if got := fake.Sent[len(fake.Sent)-1]; got[0][0] != want {
+			t.Fatalf("undo sent %v, want %d", got, want)
+		}
+	}
+	if _, err := runRoot(t, "", rootOptions, "undo"); err == nil {
+		t.Fatal("expected nothing left to undo")
+	}
+}
+
+func TestUndoTarget(t *testing.T) {
+	t.Parallel()
+
+	sent := func(code int, command string) historyEntry {
+		return historyEntry{Command: command, Characters: [][]int{{code}}, Profile: "p", Result: historySent}
+	}
+	entries := []historyEntry{
+		sent(1, "send"),
+		{Command: "send", Characters: [][]int{{9}}, Profile: "other", Result: historySent},
+		sent(2, "send"),
+		{Command: "send", Characters: [][]int{{8}}, Profile: "p", Result: historyFailed},
+		sent(3, "send"),
+		sent(2, historyUndo),
+		sent(4, "clear"),
+	}
+	target, ok := undoTarget(entries, "p")
+	if !ok || target.Characters[0][0] != 2 {
+		t.Fatalf("got %+v, %v", target, ok)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	if got, err := parseSince("2h", now); err != nil || !got.Equal(now.Add(-2*time.Hour)) {
+		t.Fatalf("duration: %v %v", got, err)
+	}
+	if got, err := parseSince("2026-01-01T00:00:00Z", now); err != nil || got.Day() != 1 {
+		t.Fatalf("time: %v %v", got, err)
+	}
+	if _, err := parseSince("yesterday", now); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,340 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vbcli/internal/playlist"
+	"vbcli/internal/state"
+	"vbcli/internal/vbml"
+	"vbcli/vestaboard"
+)
+
+const playlistsDir = "playlists"
+
+type playlistRunOptions struct {
+	loops         int
+	shuffle       bool
+	skipUnchanged bool
+	restart       bool
+}
+
+// playlistPosition is saved after every item so the next run resumes there.
+type playlistPosition struct {
+	// Checksum identifies the playlist contents; an edited file starts
+	// over.
+	Checksum string `json:"checksum"`
+	Shuffle  bool   `json:"shuffle"`
+	Cycle    int    `json:"cycle"`
+	Order    []int  `json:"order"`
+	// Next is the index in Order of the item to show next.
+	Next int `json:"next"`
+}
+
+func newPlaylistCmd(stdout, stderr io.Writer, opts *options) *cobra.Command {
+	playlistCmd := &cobra.Command{
+		Use:   "playlist",
+		Short: "Rotate through a list of messages",
+		Args:  exactArgsWithHelp(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			_ = cmd.Help()
+			return errors.New("a subcommand is required: run or validate")
+		},
+	}
+
+	var runOpts playlistRunOptions
+	runCmd := &cobra.Command{
+		Use:   "run <playlist.yaml>",
+		Short: "Show each playlist item for its duration, in a loop",
+		Long: `Show each playlist item for its duration, in a loop.
+
+The position in the playlist is saved in the state directory after every
+item, so running the same file again resumes where the last run stopped.
+Editing the file starts it over.`,
+		Args: exactArgsWithHelp(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPlaylist(cmd, stdout, stderr, opts, args[0], runOpts)
+		},
+	}
+	runCmd.Flags().IntVar(&runOpts.loops, "loops", 0, "Stop after this many full cycles through the playlist (0 loops forever)")
+	runCmd.Flags().BoolVar(&runOpts.shuffle, "shuffle", false, "Shuffle each cycle (also set with shuffle: true in the file)")
+	runCmd.Flags().BoolVar(&runOpts.skipUnchanged, "skip-unchanged", false, "Do not resend an item the board already shows")
+	runCmd.Flags().BoolVar(&runOpts.restart, "restart", false, "Start from the first item instead of the saved position")
+	addForceFlag(runCmd, opts)
+
+	validateCmd := &cobra.Command{
+		Use:   "validate <playlist.yaml>",
+		Short: "Render every playlist item offline and report errors",
+		Args:  exactArgsWithHelp(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPlaylistValidate(cmd, stdout, args[0])
+		},
+	}
+
+	playlistCmd.AddCommand(runCmd, validateCmd)
+	return playlistCmd
+}
+
+func runPlaylist(cmd *cobra.Command, stdout, stderr io.Writer, opts *options, path string, runOpts playlistRunOptions) error {
+	if runOpts.loops < 0 {
+		return usageError(cmd, fmt.Errorf("invalid --loops %d", runOpts.loops))
+	}
+	list, err := playlist.Load(path)
+	if err != nil {
+		return err
+	}
+	for _, item := range list.Items {
+		if err := checkPlaylistItem(item); err != nil {
+			return fmt.Errorf("%s: item %q: %w", path, item.Name, err)
+		}
+	}
+	board, err := buildBoard(stderr, opts)
+	if err != nil {
+		return err
+	}
+	formatter, err := buildFormatter(stderr, opts, board)
+	if err != nil {
+		return err
+	}
+	store, err := openState(opts)
+	if err != nil {
+		return err
+	}
+	positionFile, checksum, err := playlistKeys(path)
+	if err != nil {
+		return err
+	}
+
+	shuffle := runOpts.shuffle || list.Shuffle
+	var rng *rand.Rand
+	if shuffle {
+		rng = rand.New(rand.NewSource(opts.clock.Now().UnixNano()))
+	}
+	var pos playlistPosition
+	if !runOpts.restart {
+		if err := store.ReadJSON(positionFile, &pos); err != nil && !errors.Is(err, os.ErrNotExist) {
+			warn(stderr, fmt.Errorf("starting the playlist over: %w", err))
+		}
+	}
+	if !pos.valid(checksum, shuffle, len(list.Items)) {
+		pos = playlistPosition{Checksum: checksum, Shuffle: shuffle, Order: list.Order(rng)}
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	original, restore := keepTransition(ctx, stderr, board, list)
+	defer restore()
+
+	// A resumed cycle finishes what the last run started and does not count
+	// toward --loops.
+	loops, fromStart := 0, pos.Next == 0
+	for {
+		if pos.Next >= len(pos.Order) {
+			if fromStart {
+				loops++
+			}
+			fromStart = true
+			if runOpts.loops > 0 && loops >= runOpts.loops {
+				return savePlaylistPosition(store, positionFile, pos)
+			}
+			pos.Cycle++
+			pos.Order = list.Order(rng)
+			pos.Next = 0
+		}
+		if err := savePlaylistPosition(store, positionFile, pos); err != nil {
+			warn(stderr, err)
+		}
+
+		item := list.Items[pos.Order[pos.Next]]
+		result, err := showPlaylistItem(ctx, stderr, opts, board, formatter, commandName(cmd), item, original, runOpts.skipUnchanged)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			warn(stderr, fmt.Errorf("item %q: %w", item.Name, err))
+		}
+		if err := logFiring(stdout, opts.clock.Now().Format(time.RFC3339), item.Name, result); err != nil {
+			return err
+		}
+		if err := sleepContext(ctx, opts.clock, item.Duration); err != nil {
+			return nil
+		}
+		pos.Next++
+	}
+}
+
+// showPlaylistItem shows items without a transition of their own with
+// original, when it is set.
+func showPlaylistItem(ctx context.Context, stderr io.Writer, opts *options, board vestaboard.Board, formatter vestaboard.Formatter, command string, item *playlist.Item, original *boardTransition, skipUnchanged bool) (string, error) {
+	input := playlistItemInput(item)
+	characters, model, err := playlistItemStyle(item).render(ctx, formatter, input)
+	if err != nil {
+		return historyFailed, err
+	}
+	if skipUnchanged {
+		unchanged, err := boardShows(ctx, opts, board, characters)
+		if err != nil {
+			warn(stderr, fmt.Errorf("check the board: %w", err))
+		}
+		if unchanged {
+			return sendStatusUnchanged, nil
+		}
+	}
+	transition := original
+	if item.Transition != nil {
+		transitionType, _ := resolveTransitionType(item.Transition.Type)
+		transitionSpeed, _ := resolveTransitionSpeed(item.Transition.Speed)
+		transition = &boardTransition{Transition: transitionType, TransitionSpeed: transitionSpeed}
+	}
+	if transition != nil {
+		if err := board.SetTransition(ctx, transition.Transition, transition.TransitionSpeed); err != nil {
+			return historyFailed, fmt.Errorf("set transition: %w", err)
+		}
+	}
+	err = deliver(ctx, stderr, opts, board, delivery{Command: command, Source: input, Model: model, Characters: characters})
+	return sendResult(err), err
+}
+
+type boardTransition struct {
+	Transition      string `json:"transition"`
+	TransitionSpeed string `json:"transitionSpeed"`
+}
+
+func keepTransition(ctx context.Context, stderr io.Writer, board vestaboard.Board, list *playlist.Playlist) (*boardTransition, func()) {
+	sets := false
+	for _, item := range list.Items {
+		sets = sets || item.Transition != nil
+	}
+	if !sets {
+		return nil, func() {}
+	}
+	var original boardTransition
+	body, err := board.GetTransition(ctx)
+	if err == nil {
+		err = json.Unmarshal(body, &original)
+	}
+	if err == nil && original.Transition == "" {
+		err = errors.New("no transition in the response")
+	}
+	if err != nil {
+		warn(stderr, fmt.Errorf("read the transition, which will not be restored: %w", err))
+		return nil, func() {}
+	}
+	return &original, func() {
+		if err := board.SetTransition(context.WithoutCancel(ctx), original.Transition, original.TransitionSpeed); err != nil {
+			warn(stderr, fmt.Errorf("restore the transition: %w", err))
+		}
+	}
+}
+
+func runPlaylistValidate(cmd *cobra.Command, stdout io.Writer, path string) error {
+	list, err := playlist.Load(path)
+	if err != nil {
+		return err
+	}
+
+	invalid := 0
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ITEM\tDURATION\tWEIGHT\tMESSAGE")
+	for _, item := range list.Items {
+		message, err := validatePlaylistItem(cmd.Context(), item)
+		if err != nil {
+			invalid++
+			message = "error: " + err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", item.Name, item.Duration, item.Weight, message)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d playlist items are invalid", invalid, len(list.Items))
+	}
+	return nil
+}
+
+func validatePlaylistItem(ctx context.Context, item *playlist.Item) (string, error) {
+	if err := checkPlaylistItem(item); err != nil {
+		return "", err
+	}
+	characters, model, err := playlistItemStyle(item).render(ctx, vbml.Renderer{}, playlistItemInput(item))
+	if err != nil {
+		return "", err
+	}
+	return summarizeLayout(characters, model), nil
+}
+
+func checkPlaylistItem(item *playlist.Item) error {
+	if err := playlistItemStyle(item).validate(playlistItemInput(item)); err != nil {
+		return err
+	}
+	if item.Transition == nil {
+		return nil
+	}
+	if _, err := resolveTransitionType(item.Transition.Type); err != nil {
+		return fmt.Errorf("transition: %w", err)
+	}
+	if _, err := resolveTransitionSpeed(item.Transition.Speed); err != nil {
+		return fmt.Errorf("transition: %w", err)
+	}
+	return nil
+}
+
+func playlistItemStyle(item *playlist.Item) messageStyle {
+	return messageStyle{Model: item.Model, Align: item.Align, Justify: item.Justify}
+}
+
+func playlistItemInput(item *playlist.Item) string {
+	if len(item.Characters) == 0 {
+		return item.Message
+	}
+	out, _ := json.Marshal(item.Characters)
+	return string(out)
+}
+
+func playlistKeys(path string) (string, string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", fmt.Errorf("resolve playlist path: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("read playlist: %w", err)
+	}
+	name := sha256.Sum256([]byte(abs))
+	sum := sha256.Sum256(data)
+	return filepath.Join(playlistsDir, hex.EncodeToString(name[:8])+".json"), hex.EncodeToString(sum[:]), nil
+}
+
+func (p playlistPosition) valid(checksum string, shuffle bool, items int) bool {
+	if p.Checksum != checksum || p.Shuffle != shuffle || len(p.Order) == 0 || p.Next < 0 || p.Next > len(p.Order) {
+		return false
+	}
+	for _, index := range p.Order {
+		if index < 0 || index >= items {
+			return false
+		}
+	}
+	return true
+}
+
+func savePlaylistPosition(store *state.Store, name string, pos playlistPosition) error {
+	if err := store.WriteJSON(name, pos); err != nil {
+		return fmt.Errorf("save playlist position: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"vbcli/vestaboard"
+)
+
+func writePlaylistFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "playlist.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write playlist: %v", err)
+	}
+	return path
+}
+
+func TestPlaylistRunLoopsWithWeights(t *testing.T) {
+	t.Parallel()
+
+	path := writePlaylistFile(t, `
+duration: 10s
+items:
+  - name: a
+    characters: [[1]]
+    weight: 2
+  - name: b
+    characters: [[2]]
+    duration: 1m
+    transition: {type: curtain, speed: gentle}
+`)
+	fake := vestaboard.NewFakeBoard(nil)
+	clock := newInstantClock(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	out, err := runRoot(t, "", []Option{WithBoard(fake), WithClock(clock)}, "playlist", "run", path, "--loops", "2")
+	if err != nil {
+		t.Fatalf("playlist run: %v", err)
+	}
+	if want := [][][]int{{{1}}, {{2}}, {{1}}, {{1}}, {{2}}, {{1}}}; !reflect.DeepEqual(fake.Sent, want) {
+		t.Fatalf("sent %v, want %v", fake.Sent, want)
+	}
+	want := []time.Duration{10 * time.Second, time.Minute, 10 * time.Second, 10 * time.Second, time.Minute, 10 * time.Second}
+	if !reflect.DeepEqual(clock.Slept(), want) {
+		t.Fatalf("sleeps %v, want %v", clock.Slept(), want)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 6 || lines[1] != "2026-10-16T09:00:10Z b sent" {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	// b's transition is not left behind.
+	body, err := fake.GetTransition(context.Background())
+	if err != nil || string(body) != `{"transition":"classic","transitionSpeed":"fast"}` {
+		t.Fatalf("transition after the run: %s %v", body, err)
+	}
+}
+
+// transitionRecorder notes the board's transition at every send.
+type transitionRecorder struct {
+	*vestaboard.FakeBoard
+	seen []string
+}
+
+func (r *transitionRecorder) SendCharacters(ctx context.Context, characters [][]int) error {
+	body, err := r.GetTransition(ctx)
+	if err != nil {
+		return err
+	}
+	r.seen = append(r.seen, string(body))
+	return r.FakeBoard.SendCharacters(ctx, characters)
+}
+
+func TestPlaylistRunRestoresTransitionBetweenItems(t *testing.T) {
+	t.Parallel()
+
+	path := writePlaylistFile(t, `
+items:
+  - name: a
+    characters: [[1]]
+    transition: {type: curtain, speed: gentle}
+  - name: b
+    characters: [[2]]
+`)
+	board := &transitionRecorder{FakeBoard: vestaboard.NewFakeBoard(nil)}
+	clock := newInstantClock(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	if _, err := runRoot(t, "", []Option{WithBoard(board), WithClock(clock)}, "playlist", "run", path, "--loops", "1"); err != nil {
+		t.Fatalf("playlist run: %v", err)
+	}
+	want := []string{
+		`{"transition":"curtain","transitionSpeed":"gentle"}`,
+		`{"transition":"classic","transitionSpeed":"fast"}`,
+	}
+	if !reflect.DeepEqual(board.seen, want) {
+		t.Fatalf("transitions at each send %v, want %v", board.seen, want)
+	}
+}
+
+func TestPlaylistRunResumesAndSkipsUnchanged(t *testing.T) {
+	t.Parallel()
+
+	path := writePlaylistFile(t, `
+items:
+  - name: a
+    characters: [[1]]
+  - name: b
+    characters: [[2]]
+  - name: c
+    characters: [[3]]
+`)
+	stateDir := t.TempDir()
+	fake := vestaboard.NewFakeBoard(nil)
+	clock := newStepClock(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	stop := startRoot(t, []Option{WithBoard(fake), WithClock(clock), WithStateDir(stateDir)}, "playlist", "run", path)
+	clock.fire(clock.next(t))
+	clock.next(t)
+	if _, err := stop(); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+
+	// b was on the board when the first run stopped, so the second run
+	// starts with it and does not resend it. The resumed cycle is not
+	// counted, so one full cycle follows.
+	out, err := runRoot(t, "", []Option{WithBoard(fake), WithClock(newInstantClock(time.Unix(0, 0))), WithStateDir(stateDir)},
+		"playlist", "run", path, "--loops", "1", "--skip-unchanged")
+	if err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if !strings.Contains(out, " b unchanged\n") || !strings.Contains(out, " c sent\n") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	if want := [][][]int{{{1}}, {{2}}, {{3}}, {{1}}, {{2}}, {{3}}}; !reflect.DeepEqual(fake.Sent, want) {
+		t.Fatalf("sent %v, want %v", fake.Sent, want)
+	}
+
+	// Editing the playlist starts it over.
+	if err := os.WriteFile(path, []byte("items:\n  - name: z\n    characters: [[9]]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out, err = runRoot(t, "", []Option{WithBoard(fake), WithClock(newInstantClock(time.Unix(0, 0))), WithStateDir(stateDir)},
+		"playlist", "run", path, "--loops", "1")
+	if err != nil || !strings.HasSuffix(out, " z sent\n") {
+		t.Fatalf("edited playlist: %q %v", out, err)
+	}
+}
+
+func TestPlaylistValidate(t *testing.T) {
+	t.Parallel()
+
+	path := writePlaylistFile(t, `
+duration: 30s
+items:
+  - name: hello
+    message: "Hello"
+  - name: raw
+    characters: [[8, 9]]
+`)
+	out, err := runRoot(t, "", nil, "playlist", "validate", path)
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	want := `ITEM   DURATION  WEIGHT  MESSAGE
+hello  30s       1       HELLO
+raw    30s       1       HI
+`
+	if out != want {
+		t.Fatalf("output:\n%s\nwant:\n%s", out, want)
+	}
+
+	bad := writePlaylistFile(t, `
+items:
+  - name: ok
+    message: "fine"
+  - name: wide
+    message: "x"
+    justify: sideways
+  - name: spin
+    message: "y"
+    transition: {type: spin, speed: fast}
+`)
+	out, err = runRoot(t, "", nil, "playlist", "validate", bad)
+	if err == nil || err.Error() != "2 of 3 playlist items are invalid" {
+		t.Fatalf("expected invalid items, got %v", err)
+	}
+	if !strings.Contains(out, `error: invalid --justify "sideways"`) || !strings.Contains(out, "error: transition: invalid --type") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
+
+func TestPlaylistRunRejectsInvalidItems(t *testing.T) {
+	t.Parallel()
+
+	path := writePlaylistFile(t, "items:\n  - message: hi\n    model: wall\n")
+	if _, err := runRoot(t, "", []Option{WithBoard(vestaboard.NewFakeBoard(nil))}, "playlist", "run", path); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := runRoot(t, "", nil, "playlist", "run", path, "--loops", "-1"); err == nil {
+		t.Fatal("expected error for negative --loops")
+	}
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vbcli/internal/config"
+	"vbcli/internal/policy"
+)
+
+const displayTimeFormat = "2006-01-02 15:04 MST"
+
+func newPolicyCmd(stdout io.Writer, opts *options) *cobra.Command {
+	policyCmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Inspect the quiet hours policy from the config file",
+		Args:  exactArgsWithHelp(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			_ = cmd.Help()
+			return errors.New("a subcommand is required: check")
+		},
+	}
+
+	var at string
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Print whether a send now, or --at a time, would be allowed",
+		Long: `Print whether a send now, or --at a time, would be allowed.
+
+Prints "allowed", or the action of the quiet hours window that applies and
+when it ends. Exits with status 9 when the send would not go through
+unchanged.`,
+		Args: exactArgsWithHelp(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runPolicyCheck(cmd, stdout, opts, at)
+		},
+	}
+	checkCmd.Flags().StringVar(&at, "at", "", "Check a send at this RFC 3339 time instead of now")
+
+	policyCmd.AddCommand(checkCmd)
+	return policyCmd
+}
+
+func runPolicyCheck(cmd *cobra.Command, stdout io.Writer, opts *options, at string) error {
+	when := opts.clock.Now()
+	if at != "" {
+		parsed, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			return usageError(cmd, fmt.Errorf("invalid --at %q (expected an RFC 3339 time)", at))
+		}
+		when = parsed
+	}
+	cfg, err := loadConfig(opts)
+	if err != nil {
+		return err
+	}
+
+	decision := cfg.Policy.Check(when)
+	result := "allowed"
+	if !decision.Allowed() {
+		result = describeDecision(decision)
+	}
+	if _, err := fmt.Fprintln(stdout, result); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	if !decision.Allowed() {
+		return ErrNotAllowed
+	}
+	return nil
+}
+
+func addForceFlag(cmd *cobra.Command, opts *options) {
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Send even when the quiet hours policy would block, queue or blank the message")
+}
+
+// applyPolicy returns the characters to send under the quiet hours policy,
+// with a *quietHoursError for a send that a window blocks or holds.
+func applyPolicy(stderr io.Writer, opts *options, characters [][]int) ([][]int, error) {
+	if opts.force {
+		return characters, nil
+	}
+	cfg, err := loadConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	decision := cfg.Policy.Check(opts.clock.Now())
+	if decision.Allowed() {
+		return characters, nil
+	}
+	if window := decision.Window; window.Action == policy.ActionBlank {
+		return window.Replacement(characters), nil
+	}
+	return characters, &quietHoursError{Decision: decision}
+}
+
+// quietHoursError is returned for a send that a quiet hours window blocks
+// or holds. A held send is neither sent nor recorded.
+type quietHoursError struct {
+	Decision policy.Decision
+}
+
+func (e *quietHoursError) Error() string {
+	return fmt.Sprintf("%s: %s; use --force to send anyway", ErrQuietHours, describeDecision(e.Decision))
+}
+
+func (e *quietHoursError) Unwrap() error {
+	return ErrQuietHours
+}
+
+func quietUntil(err error) (time.Time, bool) {
+	var quiet *quietHoursError
+	if errors.As(err, &quiet) && !quiet.Decision.Until.IsZero() {
+		return quiet.Decision.Until, true
+	}
+	return time.Time{}, false
+}
+
+// deferredUntil is like quietUntil, but only for queue windows.
+func deferredUntil(err error) (time.Time, bool) {
+	var quiet *quietHoursError
+	if errors.As(err, &quiet) && quiet.Decision.Window.Action == policy.ActionQueue {
+		return quietUntil(err)
+	}
+	return time.Time{}, false
+}
+
+func sendResult(err error) string {
+	if until, ok := deferredUntil(err); ok {
+		return "deferred until " + until.Format(displayTimeFormat)
+	}
+	switch {
+	case err == nil:
+		return historySent
+	case errors.Is(err, ErrQuietHours):
+		return historyBlocked
+	}
+	return historyFailed
+}
+
+func describeDecision(decision policy.Decision) string {
+	if decision.Until.IsZero() {
+		return fmt.Sprintf("%s with no end (%s)", decision.Window.Action, decision.Window.Name)
+	}
+	return fmt.Sprintf("%s until %s (%s)", decision.Window.Action, decision.Until.Format(displayTimeFormat), decision.Window.Name)
+}
+
+func loadConfig(opts *options) (*config.Config, error) {
+	path := resolveSetting(opts.configFile, config.EnvFile)
+	if path != "" {
+		return config.Load(path, true)
+	}
+	path, err := config.DefaultPath(os.Getenv)
+	if err != nil {
+		// Without a home directory there is no default config to read.
+		return &config.Config{}, nil
+	}
+	return config.Load(path, false)
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"vbcli/vestaboard"
+)
+
+const quietHoursConfig = `
+policy:
+  windows:
+    - name: night
+      start: "22:00"
+      end: "07:00"
+      timezone: UTC
+      action: queue
+    - name: weekend
+      days: [sat-sun]
+      timezone: UTC
+      action: block
+    - name: lunch
+      start: "12:00"
+      end: "13:00"
+      timezone: UTC
+      action: blank
+`
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func TestPolicyCheck(t *testing.T) {
+	t.Parallel()
+
+	configFile := writeConfig(t, quietHoursConfig)
+	tests := []struct {
+		at   string
+		want string
+	}{
+		{at: "2026-10-16T15:00:00Z", want: "allowed"},
+		{at: "2026-10-16T23:00:00Z", want: "queue until 2026-10-17 07:00 UTC (night)"},
+		{at: "2026-10-17T15:00:00Z", want: "block until 2026-10-19 00:00 UTC (weekend)"},
+		{at: "2026-10-16T12:30:00+00:00", want: "blank until 2026-10-16 13:00 UTC (lunch)"},
+	}
+	for _, tc := range tests {
+		out, err := runRoot(t, "", []Option{WithConfigFile(configFile)}, "policy", "check", "--at", tc.at)
+		if strings.TrimSpace(out) != tc.want {
+			t.Fatalf("%s: got %q, want %q", tc.at, out, tc.want)
+		}
+		if (tc.want == "allowed") != (err == nil) || (err != nil && ExitCode(err) != ExitQuietHours) {
+			t.Fatalf("%s: unexpected error %v", tc.at, err)
+		}
+	}
+
+	if _, err := runRoot(t, "", []Option{WithConfigFile(configFile)}, "policy", "check", "--at", "tonight"); err == nil {
+		t.Fatal("expected error for an invalid --at")
+	}
+	if _, err := runRoot(t, "", []Option{WithConfigFile(filepath.Join(t.TempDir(), "missing.yaml"))}, "policy", "check"); err == nil {
+		t.Fatal("expected error for a missing --config file")
+	}
+}
+
+func TestSendBlockedByQuietHours(t *testing.T) {
+	t.Parallel()
+
+	configFile := writeConfig(t, quietHoursConfig)
+	fake := vestaboard.NewFakeBoard(nil)
+	stateDir := t.TempDir()
+	// Saturday afternoon.
+	clock := newInstantClock(time.Date(2026, 10, 17, 15, 0, 0, 0, time.UTC))
+	rootOptions := []Option{WithBoard(fake), WithClock(clock), WithStateDir(stateDir), WithConfigFile(configFile)}
+
+	_, err := runRoot(t, "", rootOptions, "send-raw", "[[1]]")
+	if !errors.Is(err, ErrQuietHours) || !strings.Contains(err.Error(), "weekend") {
+		t.Fatalf("expected quiet hours error, got %v", err)
+	}
+	if len(fake.Sent) != 0 {
+		t.Fatalf("blocked send reached the board: %v", fake.Sent)
+	}
+	out, err := runRoot(t, "", rootOptions, "history", "--json")
+	if err != nil || !strings.Contains(out, `"result":"blocked"`) {
+		t.Fatalf("blocked send should be in the history: %s %v", out, err)
+	}
+
+	if _, err := runRoot(t, "", rootOptions, "send-raw", "--force", "[[1]]"); err != nil {
+		t.Fatalf("send-raw --force: %v", err)
+	}
+	if len(fake.Sent) != 1 {
+		t.Fatalf("forced send did not reach the board: %v", fake.Sent)
+	}
+}
+
+func TestSendQueuedUntilQuietHoursEnd(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard(nil)
+	// Friday 23:00; the night window ends at 07:00 and the weekend window
+	// does not queue, so the send is held and then blocked.
+	clock := newInstantClock(time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC))
+	rootOptions := []Option{WithBoard(fake), WithClock(clock), WithConfigFile(writeConfig(t, quietHoursConfig))}
+	if _, err := runRoot(t, "", rootOptions, "send-raw", "[[1]]"); !errors.Is(err, ErrQuietHours) {
+		t.Fatalf("expected quiet hours error, got %v", err)
+	}
+	if want := []time.Duration{8 * time.Hour}; !reflect.DeepEqual(clock.Slept(), want) {
+		t.Fatalf("slept %v, want %v", clock.Slept(), want)
+	}
+
+	// Thursday 23:00 waits for the night to end and then sends.
+	clock = newInstantClock(time.Date(2026, 10, 15, 23, 0, 0, 0, time.UTC))
+	rootOptions = []Option{WithBoard(fake), WithClock(clock), WithConfigFile(writeConfig(t, quietHoursConfig))}
+	if _, err := runRoot(t, "", rootOptions, "send-raw", "[[1]]"); err != nil {
+		t.Fatalf("queued send: %v", err)
+	}
+	if len(fake.Sent) != 1 || !reflect.DeepEqual(clock.Slept(), []time.Duration{8 * time.Hour}) {
+		t.Fatalf("sent %v after sleeping %v", fake.Sent, clock.Slept())
+	}
+}
+
+func TestTemporarySendFailsDuringQueueWindow(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{1, 2}, {3, 4}})
+	clock := newInstantClock(time.Date(2026, 10, 15, 23, 0, 0, 0, time.UTC))
+	rootOptions := []Option{WithBoard(fake), WithClock(clock), WithConfigFile(writeConfig(t, quietHoursConfig))}
+	_, err := runRoot(t, "", rootOptions, "--renderer", "local", "send", "--for", "5m", "Back soon")
+	if ExitCode(err) != ExitQuietHours || !strings.Contains(err.Error(), "until 2026-10-16 07:00 UTC") {
+		t.Fatalf("expected quiet hours error, got %v", err)
+	}
+	if len(fake.Sent) != 0 || len(clock.Slept()) != 0 {
+		t.Fatalf("sent %v after sleeping %v", fake.Sent, clock.Slept())
+	}
+}
+
+func TestClockRendersFreshFrameAfterQueueWindow(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard(nil)
+	clock := newStepClock(time.Date(2026, 10, 15, 6, 30, 20, 0, time.UTC))
+	rootOptions := []Option{WithBoard(fake), WithClock(clock), WithConfigFile(writeConfig(t, quietHoursConfig))}
+	stop := startRoot(t, rootOptions, "clock", "--date=false")
+	w := clock.next(t)
+	if want := 29*time.Minute + 40*time.Second; w.d != want {
+		t.Fatalf("wait %s, want %s", w.d, want)
+	}
+	clock.fire(w)
+	clock.next(t)
+	out, err := stop()
+	if err != nil {
+		t.Fatalf("clock: %v", err)
+	}
+	if want := []string{"07:00"}; !reflect.DeepEqual(sentText(fake), want) {
+		t.Fatalf("sent %q, want %q", sentText(fake), want)
+	}
+	want := "2026-10-15T06:30:20Z 06:30 deferred until 2026-10-15 07:00 UTC\n2026-10-15T07:00:00Z 07:00 sent\n"
+	if out != want {
+		t.Fatalf("log %q, want %q", out, want)
+	}
+}
+
+func TestSendBlankedDuringQuietHours(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard(nil)
+	clock := newInstantClock(time.Date(2026, 10, 16, 12, 15, 0, 0, time.UTC))
+	rootOptions := []Option{WithBoard(fake), WithClock(clock), WithConfigFile(writeConfig(t, quietHoursConfig))}
+	if _, err := runRoot(t, "", rootOptions, "send-raw", "[[1,2],[3,4]]"); err != nil {
+		t.Fatalf("send-raw: %v", err)
+	}
+	if want := [][][]int{{{0, 0}, {0, 0}}}; !reflect.DeepEqual(fake.Sent, want) {
+		t.Fatalf("sent %v, want %v", fake.Sent, want)
+	}
+}
+
+func TestSendRejectsInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard(nil)
+	configFile := writeConfig(t, "policy:\n  windows:\n    - action: snooze\n")
+	if _, err := runRoot(t, "", []Option{WithBoard(fake), WithConfigFile(configFile)}, "send-raw", "[[1]]"); err == nil || !strings.Contains(err.Error(), configFile) {
+		t.Fatalf("expected config error, got %v", err)
+	}
+	if len(fake.Sent) != 0 {
+		t.Fatal("send must not go ahead with an invalid policy")
+	}
+}
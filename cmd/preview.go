@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"vbcli/internal/codec"
+	"vbcli/internal/preview"
+)
+
+func newPreviewCmd(stdin io.Reader, stdout, stderr io.Writer, opts *options) *cobra.Command {
+	previewCmd := &cobra.Command{
+		Use:   "preview [message|characters-json|-]",
+		Short: "Draw a message or characters matrix in the terminal without sending it",
+		Args:  maxArgsWithHelp(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPreview(cmd, stdin, stdout, stderr, opts, args)
+		},
+	}
+	previewCmd.Flags().StringVarP(&opts.model, flagModel, "m", "", "VBML model for preview: flagship or note")
+	previewCmd.Flags().StringVarP(&opts.align, "align", "a", "center", "VBML align for preview: top, center, or bottom")
+	previewCmd.Flags().StringVarP(&opts.justify, "justify", "j", "center", "VBML justify for preview: left, center, right, or justified")
+	return previewCmd
+}
+
+func runPreview(cmd *cobra.Command, stdin io.Reader, stdout, stderr io.Writer, opts *options, args []string) error {
+	resolved, err := resolveCommandInput(cmd, stdin, args, "message")
+	if err != nil {
+		return err
+	}
+	formatter, err := buildFormatter(stderr, opts, opts.board)
+	if err != nil {
+		return err
+	}
+	characters, model, err := renderInput(cmd, formatter, opts, resolved)
+	if err != nil {
+		return err
+	}
+	return renderPreview(stdout, opts, characters, model, nil)
+}
+
+func renderPreview(stdout io.Writer, opts *options, characters [][]int, model string, highlight func(row, column int) bool) error {
+	mode, err := preview.ParseMode(opts.color, stdout)
+	if err != nil {
+		return err
+	}
+	return preview.Render(stdout, characters, preview.Options{
+		Mode:      mode,
+		Codec:     codec.ForModel(model),
+		Highlight: highlight,
+	})
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"vbcli/vestaboard"
+)
+
+func TestPreviewRawCharacters(t *testing.T) {
+	t.Parallel()
+
+	out, err := runRoot(t, "", nil, "--color", "none", "preview", "[[8,9],[63,0]]")
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+	want := "+------+\n| H  I |\n| r    |\n+------+\n"
+	if out != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestPreviewTemplateWithLocalRenderer(t *testing.T) {
+	t.Parallel()
+
+	out, err := runRoot(t, "", nil, "--color", "none", "--renderer", "local", "preview", "-m", "note", "hi")
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 5 || !strings.Contains(lines[2], " H  I ") {
+		t.Fatalf("unexpected preview:\n%s", out)
+	}
+}
+
+func TestGetRenderWithFakeBoard(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{1}})
+	out, err := runRoot(t, "", []Option{WithBoard(fake)}, "--color", "none", "get", "--render")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if out != "+---+\n| A |\n+---+\n" {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
+
+func TestSendRenderRequiresFormat(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard(nil)
+	if _, err := runRoot(t, "", []Option{WithBoard(fake)}, "send", "--render", "hi"); err == nil {
+		t.Fatal("expected error for --render without --format")
+	}
+	if len(fake.Sent) != 0 {
+		t.Fatal("nothing should be sent")
+	}
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vbcli/internal/queue"
+)
+
+type enqueueOptions struct {
+	priority string
+	ttl      time.Duration
+}
+
+func newEnqueueCmd(stdin io.Reader, stdout, stderr io.Writer, opts *options) *cobra.Command {
+	var enqueueOpts enqueueOptions
+	enqueueCmd := &cobra.Command{
+		Use:   "enqueue [message|-]",
+		Short: "Add a message to the queue shown by daemon --queue",
+		Long: `Add a message to the queue shown by daemon --queue.
+
+The message is rendered now, like send, and kept in the state directory
+until it expires or is dropped. The daemon shows the highest-priority item
+that has not expired, oldest first within a priority. Prints the item ID.`,
+		Args: maxArgsWithHelp(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEnqueue(cmd, stdin, stdout, stderr, opts, enqueueOpts, args)
+		},
+	}
+	enqueueCmd.Flags().StringVar(&enqueueOpts.priority, "priority", "normal", "Priority: low, normal, high, or urgent")
+	enqueueCmd.Flags().DurationVar(&enqueueOpts.ttl, "ttl", time.Hour, "Drop the message after this long (0 keeps it until dropped)")
+	enqueueCmd.Flags().StringVarP(&opts.model, flagModel, "m", "", "VBML model for enqueue: flagship or note")
+	enqueueCmd.Flags().StringVarP(&opts.align, "align", "a", "center", "VBML align for enqueue: top, center, or bottom")
+	enqueueCmd.Flags().StringVarP(&opts.justify, "justify", "j", "center", "VBML justify for enqueue: left, center, right, or justified")
+	return enqueueCmd
+}
+
+func newQueueCmd(stdout io.Writer, opts *options) *cobra.Command {
+	queueCmd := &cobra.Command{
+		Use:   "queue",
+		Short: "Inspect and remove queued messages",
+		Args:  exactArgsWithHelp(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			_ = cmd.Help()
+			return errors.New("a subcommand is required: list or drop")
+		},
+	}
+
+	var asJSON bool
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List queued messages in the order the daemon shows them",
+		Args:  exactArgsWithHelp(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runQueueList(stdout, opts, asJSON)
+		},
+	}
+	listCmd.Flags().BoolVar(&asJSON, "json", false, "Print items as a JSON array")
+
+	var all bool
+	dropCmd := &cobra.Command{
+		Use:   "drop <id>...",
+		Short: "Remove queued messages by ID, or every message with --all",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all == (len(args) > 0) {
+				return usageError(cmd, errors.New("pass item IDs or --all"))
+			}
+			return runQueueDrop(cmd, stdout, opts, all, args)
+		},
+	}
+	dropCmd.Flags().BoolVar(&all, "all", false, "Remove every queued message")
+
+	queueCmd.AddCommand(listCmd, dropCmd)
+	return queueCmd
+}
+
+func runEnqueue(cmd *cobra.Command, stdin io.Reader, stdout, stderr io.Writer, opts *options, enqueueOpts enqueueOptions, args []string) error {
+	priority, err := queue.ParsePriority(enqueueOpts.priority)
+	if err != nil {
+		return usageError(cmd, err)
+	}
+	if enqueueOpts.ttl < 0 {
+		return usageError(cmd, fmt.Errorf("invalid --ttl %s (must be 0 or more)", enqueueOpts.ttl))
+	}
+	resolved, err := resolveCommandInput(cmd, stdin, args, "message")
+	if err != nil {
+		return err
+	}
+	// Producers do not need board credentials, only a way to render.
+	formatter, err := buildFormatter(stderr, opts, opts.board)
+	if err != nil {
+		return err
+	}
+	characters, model, err := renderInput(cmd, formatter, opts, resolved)
+	if err != nil {
+		return err
+	}
+	q, err := openQueue(opts)
+	if err != nil {
+		return err
+	}
+
+	now := opts.clock.Now()
+	item := queue.Item{Priority: priority, Source: resolved, Model: model, Characters: characters}
+	if enqueueOpts.ttl > 0 {
+		item.ExpiresAt = now.Add(enqueueOpts.ttl).UTC()
+	}
+	item, err = q.Add(cmd.Context(), item, now)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(stdout, item.ID); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func runQueueList(stdout io.Writer, opts *options, asJSON bool) error {
+	q, err := openQueue(opts)
+	if err != nil {
+		return err
+	}
+	items, err := q.List(opts.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		out, err := json.Marshal(items)
+		if err != nil {
+			return fmt.Errorf("encode queue: %w", err)
+		}
+		if _, err := fmt.Fprintln(stdout, string(out)); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tPRIORITY\tEXPIRES\tMESSAGE")
+	for _, item := range items {
+		expires := "never"
+		if !item.ExpiresAt.IsZero() {
+			expires = item.ExpiresAt.Local().Format(time.DateTime)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.ID, queue.PriorityName(item.Priority), expires, summarizeLayout(item.Characters, item.Model))
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func runQueueDrop(cmd *cobra.Command, stdout io.Writer, opts *options, all bool, ids []string) error {
+	q, err := openQueue(opts)
+	if err != nil {
+		return err
+	}
+	dropped := len(ids)
+	if all {
+		if dropped, err = q.Clear(cmd.Context()); err != nil {
+			return err
+		}
+	} else if err := q.Drop(cmd.Context(), ids); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(stdout, "dropped %d item(s)\n", dropped); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func openQueue(opts *options) (*queue.Queue, error) {
+	store, err := openState(opts)
+	if err != nil {
+		return nil, err
+	}
+	return queue.New(store), nil
+}
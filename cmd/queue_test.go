@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"vbcli/vestaboard"
+)
+
+func TestEnqueueListAndDrop(t *testing.T) {
+	t.Parallel()
+
+	clock := newInstantClock(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	rootOptions := []Option{WithClock(clock), WithStateDir(t.TempDir())}
+	enqueue := func(args ...string) string {
+		t.Helper()
+		out, err := runRoot(t, "", rootOptions, append([]string{"enqueue"}, args...)...)
+		if err != nil {
+			t.Fatalf("enqueue %v: %v", args, err)
+		}
+		return strings.TrimSpace(out)
+	}
+	low := enqueue("--priority", "low", "--ttl", "0", "[[1]]")
+	high := enqueue("--priority", "high", "--ttl", "15m", "[[8,9]]")
+
+	out, err := runRoot(t, "", rootOptions, "queue", "list")
+	if err != nil {
+		t.Fatalf("queue list: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], high+" ") || !strings.Contains(lines[1], "high") ||
+		!strings.HasPrefix(lines[2], low+" ") || !strings.Contains(lines[2], "never") {
+		t.Fatalf("unexpected list:\n%s", out)
+	}
+
+	if _, err := runRoot(t, "", rootOptions, "queue", "drop", "nope"); err == nil {
+		t.Fatal("expected error for an unknown ID")
+	}
+	if out, err := runRoot(t, "", rootOptions, "queue", "drop", high); err != nil || out != "dropped 1 item(s)\n" {
+		t.Fatalf("drop: %q %v", out, err)
+	}
+	out, err = runRoot(t, "", rootOptions, "queue", "list", "--json")
+	if err != nil || !strings.Contains(out, `"id":"`+low+`"`) || strings.Contains(out, high) {
+		t.Fatalf("list --json: %s %v", out, err)
+	}
+	if out, err := runRoot(t, "", rootOptions, "queue", "drop", "--all"); err != nil || out != "dropped 1 item(s)\n" {
+		t.Fatalf("drop --all: %q %v", out, err)
+	}
+}
+
+func TestEnqueueRejectsBadFlags(t *testing.T) {
+	t.Parallel()
+
+	tests := [][]string{
+		{"enqueue", "--priority", "critical", "[[1]]"},
+		{"enqueue", "--ttl", "-1m", "[[1]]"},
+		{"enqueue", "--align", "middle", "hello"},
+		{"queue", "drop"},
+		{"queue", "drop", "--all", "abc"},
+		{"daemon"},
+		{"daemon", "--queue", "--schedule", "x.yaml"},
+		{"daemon", "--schedule", "x.yaml", "--poll", "1s"},
+		{"daemon", "--queue", "--poll", "0s"},
+	}
+	for _, args := range tests {
+		if _, err := runRoot(t, "", nil, args...); err == nil {
+			t.Fatalf("%v: expected error", args)
+		}
+	}
+}
+
+func TestQueueDaemonPreemptsAndRestores(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	fake := vestaboard.NewFakeBoard([][]int{{7}})
+	clock := newStepClock(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	rootOptions := []Option{WithBoard(fake), WithClock(clock), WithStateDir(stateDir)}
+	enqueue := func(args ...string) string {
+		t.Helper()
+		out, err := runRoot(t, "", rootOptions, append([]string{"enqueue"}, args...)...)
+		if err != nil {
+			t.Fatalf("enqueue %v: %v", args, err)
+		}
+		return strings.TrimSpace(out)
+	}
+	low := enqueue("--priority", "low", "--ttl", "0", "[[1]]")
+
+	stop := startRoot(t, rootOptions, "daemon", "--queue", "--poll", "10s")
+	w := clock.next(t)
+	if w.d != 10*time.Second {
+		t.Fatalf("wait %s, want the poll interval", w.d)
+	}
+	// An urgent message arrives while the daemon sleeps and expires at
+	// 09:00:25, before the third poll.
+	urgent := enqueue("--priority", "urgent", "--ttl", "25s", "[[2]]")
+	clock.fire(w)
+	if w = clock.next(t); w.d != 10*time.Second {
+		t.Fatalf("wait %s after showing the urgent item", w.d)
+	}
+	clock.fire(w)
+	if w = clock.next(t); w.d != 5*time.Second {
+		t.Fatalf("wait %s, want the time until the urgent item expires", w.d)
+	}
+	clock.fire(w)
+	if w = clock.next(t); w.d != 10*time.Second {
+		t.Fatalf("wait %s after the urgent item expired", w.d)
+	}
+	if _, err := runRoot(t, "", rootOptions, "queue", "drop", low); err != nil {
+		t.Fatalf("drop: %v", err)
+	}
+	clock.fire(w)
+	clock.next(t)
+
+	out, err := stop()
+	if err != nil {
+		t.Fatalf("daemon: %v", err)
+	}
+	want := "2026-10-16T09:00:00Z " + low + " sent\n" +
+		"2026-10-16T09:00:10Z " + urgent + " sent\n" +
+		"2026-10-16T09:00:25Z " + low + " sent\n" +
+		"2026-10-16T09:00:35Z previous sent\n"
+	if out != want {
+		t.Fatalf("daemon log:\n%s\nwant:\n%s", out, want)
+	}
+	if want := [][][]int{{{1}}, {{2}}, {{1}}, {{7}}}; !reflect.DeepEqual(fake.Sent, want) {
+		t.Fatalf("sent %v, want %v", fake.Sent, want)
+	}
+}
+
+func TestQueueDaemonKeepsChangedBoard(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	fake := vestaboard.NewFakeBoard([][]int{{7}})
+	clock := newStepClock(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	rootOptions := []Option{WithBoard(fake), WithClock(clock), WithStateDir(stateDir)}
+	if _, err := runRoot(t, "", rootOptions, "enqueue", "--ttl", "30s", "[[1]]"); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	stop := startRoot(t, rootOptions, "daemon", "--queue")
+	w := clock.next(t)
+	// Someone else sends while the queued item is showing.
+	if _, err := runRoot(t, "", rootOptions, "send-raw", "[[3]]"); err != nil {
+		t.Fatalf("send-raw: %v", err)
+	}
+	for expiry := time.Date(2026, 10, 16, 9, 0, 30, 0, time.UTC); clock.Now().Before(expiry); {
+		clock.fire(w)
+		w = clock.next(t)
+	}
+
+	out, err := stop()
+	if err != nil {
+		t.Fatalf("daemon: %v", err)
+	}
+	if !strings.HasSuffix(out, "previous skipped (the board has changed)\n") {
+		t.Fatalf("daemon log:\n%s", out)
+	}
+	if want := [][][]int{{{1}}, {{3}}}; !reflect.DeepEqual(fake.Sent, want) {
+		t.Fatalf("sent %v, want %v", fake.Sent, want)
+	}
+}
+
+func TestQueueDaemonHoldsItemsDuringQueueWindow(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	fake := vestaboard.NewFakeBoard([][]int{{7}})
+	clock := newStepClock(time.Date(2026, 10, 15, 6, 59, 40, 0, time.UTC))
+	rootOptions := []Option{WithBoard(fake), WithClock(clock), WithStateDir(stateDir), WithConfigFile(writeConfig(t, quietHoursConfig))}
+	enqueue := func(args ...string) string {
+		t.Helper()
+		out, err := runRoot(t, "", rootOptions, append([]string{"enqueue"}, args...)...)
+		if err != nil {
+			t.Fatalf("enqueue %v: %v", args, err)
+		}
+		return strings.TrimSpace(out)
+	}
+	low := enqueue("--priority", "low", "--ttl", "0", "[[1]]")
+	// The urgent item expires before the night window ends at 07:00.
+	urgent := enqueue("--priority", "urgent", "--ttl", "10s", "[[2]]")
+
+	stop := startRoot(t, rootOptions, "daemon", "--queue", "--poll", "15s")
+	for _, want := range []time.Duration{10 * time.Second, 10 * time.Second} {
+		w := clock.next(t)
+		if w.d != want {
+			t.Fatalf("wait %s, want %s", w.d, want)
+		}
+		clock.fire(w)
+	}
+	clock.next(t)
+
+	out, err := stop()
+	if err != nil {
+		t.Fatalf("daemon: %v", err)
+	}
+	want := "2026-10-15T06:59:40Z " + urgent + " deferred until 2026-10-15 07:00 UTC\n" +
+		"2026-10-15T07:00:00Z " + low + " sent\n"
+	if out != want {
+		t.Fatalf("daemon log:\n%s\nwant:\n%s", out, want)
+	}
+	if want := [][][]int{{{1}}}; !reflect.DeepEqual(fake.Sent, want) {
+		t.Fatalf("sent %v, want %v", fake.Sent, want)
+	}
+}
+
+func TestQueueDaemonBacksOffFailedItem(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	fake := vestaboard.NewFakeBoard([][]int{{7}})
+	fake.SendErr = errors.New("board offline")
+	clock := newStepClock(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	rootOptions := []Option{WithBoard(fake), WithClock(clock), WithStateDir(stateDir)}
+	out, err := runRoot(t, "", rootOptions, "enqueue", "--ttl", "0", "[[1]]")
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	id := strings.TrimSpace(out)
+
+	stop := startRoot(t, rootOptions, "daemon", "--queue", "--poll", "10s")
+	w := clock.next(t)
+	// The board was read before the first send; reading it again would
+	// fail and hold the item back.
+	fake.GetErr = errors.New("board offline")
+	for range 6 {
+		if w.d != 10*time.Second {
+			t.Fatalf("wait %s, want the poll interval", w.d)
+		}
+		clock.fire(w)
+		w = clock.next(t)
+	}
+	fake.SendErr = nil
+	clock.fire(w)
+	clock.next(t)
+
+	out, err = stop()
+	if err != nil {
+		t.Fatalf("daemon: %v", err)
+	}
+	// Retries come after 10s, 20s and 40s.
+	want := "2026-10-16T09:00:00Z " + id + " failed\n" +
+		"2026-10-16T09:00:10Z " + id + " failed\n" +
+		"2026-10-16T09:00:30Z " + id + " failed\n" +
+		"2026-10-16T09:01:10Z " + id + " sent\n"
+	if out != want {
+		t.Fatalf("daemon log:\n%s\nwant:\n%s", out, want)
+	}
+	if want := [][][]int{{{1}}}; !reflect.DeepEqual(fake.Sent, want) {
+		t.Fatalf("sent %v, want %v", fake.Sent, want)
+	}
+}
+
+func TestQueueDaemonRestoresBaselineAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	fake := vestaboard.NewFakeBoard([][]int{{7}})
+	clock := newStepClock(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	rootOptions := []Option{WithBoard(fake), WithClock(clock), WithStateDir(stateDir)}
+	out, err := runRoot(t, "", rootOptions, "enqueue", "--ttl", "0", "[[1]]")
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	id := strings.TrimSpace(out)
+
+	stop := startRoot(t, rootOptions, "daemon", "--queue")
+	clock.next(t)
+	if _, err := stop(); err != nil {
+		t.Fatalf("daemon: %v", err)
+	}
+	baselinePath := filepath.Join(stateDir, queueBaselineFile)
+	if _, err := os.Stat(baselinePath); err != nil {
+		t.Fatalf("baseline not saved: %v", err)
+	}
+
+	stop = startRoot(t, rootOptions, "daemon", "--queue")
+	w := clock.next(t)
+	if _, err := runRoot(t, "", rootOptions, "queue", "drop", id); err != nil {
+		t.Fatalf("drop: %v", err)
+	}
+	clock.fire(w)
+	clock.next(t)
+	out, err = stop()
+	if err != nil {
+		t.Fatalf("daemon: %v", err)
+	}
+	if out != "2026-10-16T09:00:05Z previous sent\n" {
+		t.Fatalf("daemon log %q", out)
+	}
+	if want := [][][]int{{{1}}, {{7}}}; !reflect.DeepEqual(fake.Sent, want) {
+		t.Fatalf("sent %v, want %v", fake.Sent, want)
+	}
+	if _, err := os.Stat(baselinePath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("baseline not removed after the restore: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"vbcli/internal/boardimage"
+	"vbcli/internal/codec"
+)
+
+func newRenderCmd(stdin io.Reader, stdout, stderr io.Writer, opts *options) *cobra.Command {
+	var (
+		out     string
+		scale   int
+		current bool
+	)
+	renderCmd := &cobra.Command{
+		Use:   "render --out board.png [message|characters-json|-]",
+		Short: "Write a PNG or SVG image of a message, characters matrix or the current board",
+		Args:  maxArgsWithHelp(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRender(cmd, stdin, stdout, stderr, opts, args, out, scale, current)
+		},
+	}
+	renderCmd.Flags().StringVarP(&out, "out", "o", "", "Output file; the extension selects the format: .png or .svg")
+	renderCmd.Flags().IntVar(&scale, "scale", 1, "Scale factor for the image dimensions")
+	renderCmd.Flags().BoolVar(&current, "current", false, "Render what is on the board now instead of a message")
+	renderCmd.Flags().StringVarP(&opts.model, flagModel, "m", "", "VBML model for rendering: flagship or note")
+	renderCmd.Flags().StringVarP(&opts.align, "align", "a", "center", "VBML align for rendering: top, center, or bottom")
+	renderCmd.Flags().StringVarP(&opts.justify, "justify", "j", "center", "VBML justify for rendering: left, center, right, or justified")
+	return renderCmd
+}
+
+func runRender(cmd *cobra.Command, stdin io.Reader, stdout, stderr io.Writer, opts *options, args []string, out string, scale int, current bool) error {
+	write, err := resolveImageWriter(out)
+	if err != nil {
+		return usageError(cmd, err)
+	}
+	if scale < 1 {
+		return usageError(cmd, fmt.Errorf("invalid --scale %d (must be at least 1)", scale))
+	}
+	if current && len(args) > 0 {
+		return usageError(cmd, errors.New("--current does not take a message argument"))
+	}
+
+	var (
+		characters [][]int
+		model      string
+	)
+	if current {
+		board, err := buildBoard(stderr, opts)
+		if err != nil {
+			return err
+		}
+		state, err := board.GetCurrentState(cmd.Context())
+		if err != nil {
+			return err
+		}
+		table, err := codecFor(opts.model, state.Layout)
+		if err != nil {
+			return usageError(cmd, err)
+		}
+		characters, model = state.Layout, table.Model()
+	} else {
+		resolved, err := resolveCommandInput(cmd, stdin, args, "message")
+		if err != nil {
+			return err
+		}
+		formatter, err := buildFormatter(stderr, opts, opts.board)
+		if err != nil {
+			return err
+		}
+		characters, model, err = renderInput(cmd, formatter, opts, resolved)
+		if err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := write(&buf, characters, boardimage.Options{Codec: codec.ForModel(model), Scale: scale}); err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write image: %w", err)
+	}
+	if _, err := fmt.Fprintln(stdout, out); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func resolveImageWriter(out string) (func(io.Writer, [][]int, boardimage.Options) error, error) {
+	if strings.TrimSpace(out) == "" {
+		return nil, errors.New("missing --out file")
+	}
+	switch strings.ToLower(filepath.Ext(out)) {
+	case ".png":
+		return boardimage.PNG, nil
+	case ".svg":
+		return boardimage.SVG, nil
+	default:
+		return nil, fmt.Errorf("unsupported image format %q (expected .png or .svg)", filepath.Ext(out))
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"vbcli/internal/boardimage"
+	"vbcli/vestaboard"
+)
+
+func TestRenderPNG(t *testing.T) {
+	t.Parallel()
+
+	out := filepath.Join(t.TempDir(), "board.png")
+	if _, err := runRoot(t, "", nil, "render", "--out", out, "--scale", "2", "[[8,9],[63,0]]"); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	file, err := os.Open(out)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer file.Close()
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	width, height := boardimage.Size(2, 2, boardimage.Options{Scale: 2})
+	if img.Bounds().Dx() != width || img.Bounds().Dy() != height {
+		t.Fatalf("got %v, want %dx%d", img.Bounds(), width, height)
+	}
+}
+
+func TestRenderSVGTemplateWithLocalRenderer(t *testing.T) {
+	t.Parallel()
+
+	out := filepath.Join(t.TempDir(), "board.svg")
+	if _, err := runRoot(t, "", nil, "--renderer", "local", "render", "-m", "note", "--out", out, "hi"); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	width, height := boardimage.Size(3, 15, boardimage.Options{})
+	if !strings.Contains(string(data), `width="`+strconv.Itoa(width)+`" height="`+strconv.Itoa(height)+`"`) {
+		t.Fatalf("unexpected SVG header:\n%.200s", data)
+	}
+}
+
+func TestRenderCurrentBoard(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{1, 2, 3}})
+	out := filepath.Join(t.TempDir(), "current.png")
+	if _, err := runRoot(t, "", []Option{WithBoard(fake)}, "render", "--current", "--out", out); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if _, err := os.Stat(out); err != nil {
+		t.Fatalf("expected image: %v", err)
+	}
+}
+
+func TestRenderRejectsBadFlags(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tests := [][]string{
+		{"render", "[[1]]"},
+		{"render", "--out", filepath.Join(dir, "board.jpg"), "[[1]]"},
+		{"render", "--out", filepath.Join(dir, "board.png"), "--scale", "0", "[[1]]"},
+		{"render", "--out", filepath.Join(dir, "board.png"), "--current", "[[1]]"},
+	}
+	for _, args := range tests {
+		if _, err := runRoot(t, "", nil, args...); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+}
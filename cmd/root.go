@@ -10,16 +10,30 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
-	"vbcli/internal/vestaboard"
+	"vbcli/internal/codec"
+	"vbcli/internal/config"
+	"vbcli/internal/state"
+	"vbcli/internal/vbml"
+	vbapi "vbcli/internal/vestaboard"
+	"vbcli/vestaboard"
 )
 
 const (
 	flagModel          = "model"
 	envVestaboardModel = "VESTABOARD_MODEL"
 	envVestaboardToken = "VESTABOARD_TOKEN"
+	envAPIURL          = "VESTABOARD_API_URL"
+	envVBMLURL         = "VESTABOARD_VBML_URL"
+	envTimeout         = "VESTABOARD_TIMEOUT"
+	envBackend         = "VESTABOARD_BACKEND"
+	envHost            = "VESTABOARD_HOST"
+	envLocalAPIKey     = "VESTABOARD_LOCAL_API_KEY"
+	envRenderer        = "VESTABOARD_RENDERER"
+	userAgent          = "vbcli"
 )
 
 type options struct {
@@ -29,10 +43,83 @@ type options struct {
 	transitionType  string
 	transitionSpeed string
 	verbose         bool
+	retries         int
+	retryMaxWait    time.Duration
+	minInterval     time.Duration
+	failFast        bool
+	coalesce        bool
+	apiURL          string
+	vbmlURL         string
+	timeout         time.Duration
+	backend         string
+	host            string
+	renderer        string
+	text            bool
+	render          bool
+	color           string
+	board           vestaboard.Board
+	formatter       vestaboard.Formatter
+	clock           vbapi.Clock
+	stateDir        string
+	ifChanged       string
+	sendFor         time.Duration
+	detach          bool
+	startBackground func(args []string) error
+	notifyReload    func(c chan<- os.Signal)
+	configFile      string
+	force           bool
 }
 
-func NewRootCmd(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
-	opts := &options{}
+// Option customises the root command when vbcli is embedded or tested.
+type Option func(*options)
+
+// WithBoard makes every command use board instead of building a backend
+// from flags and environment.
+func WithBoard(board vestaboard.Board) Option {
+	return func(o *options) {
+		o.board = board
+	}
+}
+
+// WithFormatter makes template commands render through formatter.
+func WithFormatter(formatter vestaboard.Formatter) Option {
+	return func(o *options) {
+		o.formatter = formatter
+	}
+}
+
+// WithClock replaces the wall clock used by commands that animate, poll or
+// run on a schedule.
+func WithClock(clock vbapi.Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// WithStateDir keeps caches, snapshots and journals in dir instead of the
+// default state directory.
+func WithStateDir(dir string) Option {
+	return func(o *options) {
+		o.stateDir = dir
+	}
+}
+
+// WithConfigFile reads the config file at path instead of the default one.
+func WithConfigFile(path string) Option {
+	return func(o *options) {
+		o.configFile = path
+	}
+}
+
+func NewRootCmd(stdin io.Reader, stdout, stderr io.Writer, rootOptions ...Option) *cobra.Command {
+	opts := &options{
+		clock:           vbapi.SystemClock,
+		startBackground: startBackground,
+		notifyReload:    notifyHangup,
+	}
+	for _, option := range rootOptions {
+		option(opts)
+	}
 
 	cmd := &cobra.Command{
 		Use:           "vbcli",
@@ -41,13 +128,27 @@ func NewRootCmd(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			_ = cmd.Help()
-			return errors.New("a subcommand is required: send-raw, send, format, clear, get, set-transition, or get-transition")
+			return errors.New("a subcommand is required: send-raw, send, format, clear, get, set-transition, get-transition, enable-local-api, decode, preview, render, animate, simulate, diff, snapshot, history, undo, watch, daemon, schedule, playlist, policy, enqueue, queue, countdown, timer, stopwatch, or clock")
 		},
 	}
 
 	cmd.SetOut(stdout)
 	cmd.SetErr(stderr)
 	cmd.PersistentFlags().BoolVarP(&opts.verbose, "verbose", "v", false, "Enable verbose HTTP logging")
+	cmd.PersistentFlags().IntVar(&opts.retries, "retries", 0, "Retry failed API requests up to this many times")
+	cmd.PersistentFlags().DurationVar(&opts.retryMaxWait, "retry-max-wait", 30*time.Second, "Longest single wait between retries, including Retry-After")
+	cmd.PersistentFlags().DurationVar(&opts.minInterval, "min-interval", 0, "Minimum time between message sends to the board from any vbcli process (0 disables)")
+	cmd.PersistentFlags().BoolVar(&opts.failFast, "fail-fast", false, "With --min-interval, fail with exit code 5 instead of waiting for a send slot")
+	cmd.PersistentFlags().BoolVar(&opts.coalesce, "coalesce", false, "With --min-interval, drop a waiting send when a newer one replaces it")
+	cmd.PersistentFlags().StringVar(&opts.apiURL, "api-url", "", "Cloud API base URL (env "+envAPIURL+")")
+	cmd.PersistentFlags().StringVar(&opts.vbmlURL, "vbml-url", "", "VBML API base URL (env "+envVBMLURL+")")
+	cmd.PersistentFlags().DurationVar(&opts.timeout, "timeout", 0, "Per-request HTTP timeout, default 15s (env "+envTimeout+")")
+	cmd.PersistentFlags().StringVar(&opts.backend, "backend", "", "API backend: cloud (default) or local (env "+envBackend+")")
+	cmd.PersistentFlags().StringVar(&opts.host, "host", "", "Board host for the local backend (env "+envHost+")")
+	cmd.PersistentFlags().StringVar(&opts.color, "color", "auto", "Terminal colours for --render and previews: auto, truecolor, 256, 16, or none")
+	cmd.PersistentFlags().StringVar(&opts.stateDir, "state-dir", opts.stateDir, "Directory for caches, snapshots and history (env "+state.EnvDir+")")
+	cmd.PersistentFlags().StringVar(&opts.configFile, "config", opts.configFile, "Config file with the quiet hours policy (env "+config.EnvFile+")")
+	cmd.PersistentFlags().StringVar(&opts.renderer, "renderer", "", "Template renderer: remote (VBML API, default) or local (experimental, offline; env "+envRenderer+")")
 
 	sendRawCmd := &cobra.Command{
 		Use:   "send-raw [characters-json|-]",
@@ -57,6 +158,7 @@ func NewRootCmd(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
 			return runSendRaw(cmd, stdin, stdout, stderr, opts, args)
 		},
 	}
+	addForceFlag(sendRawCmd, opts)
 
 	sendCmd := &cobra.Command{
 		Use:   "send [message|-]",
@@ -67,6 +169,26 @@ func NewRootCmd(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if (opts.text || opts.render) && !formatOnly {
+				return usageError(cmd, errors.New("--text and --render require --format"))
+			}
+			if opts.ifChanged != "" {
+				if formatOnly {
+					return usageError(cmd, errors.New("--if-changed cannot be used with --format"))
+				}
+				if opts.ifChanged, err = resolveIfChanged(opts.ifChanged); err != nil {
+					return usageError(cmd, err)
+				}
+			}
+			if formatOnly && opts.sendFor != 0 {
+				return usageError(cmd, errors.New("--for cannot be used with --format"))
+			}
+			if opts.sendFor < 0 {
+				return usageError(cmd, fmt.Errorf("invalid --for %s (must be positive)", opts.sendFor))
+			}
+			if opts.detach && opts.sendFor == 0 {
+				return usageError(cmd, errors.New("--detach requires --for"))
+			}
 			return runSend(cmd, stdin, stdout, stderr, opts, args, formatOnly)
 		},
 	}
@@ -74,6 +196,14 @@ func NewRootCmd(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
 	sendCmd.Flags().StringVarP(&opts.align, "align", "a", "center", "VBML align for send: top, center, or bottom")
 	sendCmd.Flags().StringVarP(&opts.justify, "justify", "j", "center", "VBML justify for send: left, center, right, or justified")
 	sendCmd.Flags().Bool("format", false, "Print VBML compose output and skip sending to Cloud API")
+	sendCmd.Flags().BoolVar(&opts.text, "text", false, "With --format, print the rendered board as text instead of JSON")
+	sendCmd.Flags().BoolVar(&opts.render, "render", false, "With --format, draw the rendered board in the terminal")
+	sendCmd.MarkFlagsMutuallyExclusive("text", "render")
+	sendCmd.Flags().StringVar(&opts.ifChanged, "if-changed", "", "Skip the send when the board already shows the message, checked live or against the last-sent cache (--if-changed=cache)")
+	sendCmd.Flags().Lookup("if-changed").NoOptDefVal = ifChangedLive
+	sendCmd.Flags().DurationVar(&opts.sendFor, "for", 0, "Show the message for this long, then restore what was on the board before")
+	sendCmd.Flags().BoolVar(&opts.detach, "detach", false, "With --for, return at once and restore from a background process")
+	addForceFlag(sendCmd, opts)
 
 	formatCmd := &cobra.Command{
 		Use:   "format <message|->",
@@ -86,6 +216,9 @@ func NewRootCmd(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
 	formatCmd.Flags().StringVarP(&opts.model, flagModel, "m", "", "VBML model for format: flagship or note")
 	formatCmd.Flags().StringVarP(&opts.align, "align", "a", "center", "VBML align for format: top, center, or bottom")
 	formatCmd.Flags().StringVarP(&opts.justify, "justify", "j", "center", "VBML justify for format: left, center, right, or justified")
+	formatCmd.Flags().BoolVar(&opts.text, "text", false, "Print the rendered board as text instead of JSON")
+	formatCmd.Flags().BoolVar(&opts.render, "render", false, "Draw the rendered board in the terminal")
+	formatCmd.MarkFlagsMutuallyExclusive("text", "render")
 
 	clearCmd := &cobra.Command{
 		Use:   "clear",
@@ -98,20 +231,27 @@ func NewRootCmd(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
 	clearCmd.Flags().StringVarP(&opts.model, flagModel, "m", "", "VBML model for clear: flagship or note")
 	clearCmd.Flags().StringVarP(&opts.align, "align", "a", "center", "VBML align for clear: top, center, or bottom")
 	clearCmd.Flags().StringVarP(&opts.justify, "justify", "j", "center", "VBML justify for clear: left, center, right, or justified")
+	addForceFlag(clearCmd, opts)
 
 	getCmd := &cobra.Command{
 		Use:   "get",
 		Short: "Fetch the current display state as JSON",
 		Args:  exactArgsWithHelp(0),
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			layoutOnly, err := cmd.Flags().GetBool("layout")
+			projection, err := resolveGetProjection(cmd)
 			if err != nil {
 				return err
 			}
-			return runGet(cmd, stdout, stderr, opts, layoutOnly)
+			return runGet(cmd, stdout, stderr, opts, projection)
 		},
 	}
 	getCmd.Flags().BoolP("layout", "l", false, "Print only currentMessage.layout")
+	getCmd.Flags().Bool("id", false, "Print only the current message id")
+	getCmd.Flags().Bool("characters", false, "Print the decoded layout as a characters JSON array")
+	getCmd.Flags().Bool("since", false, "Print how long ago the current message was created")
+	getCmd.Flags().Bool("text", false, "Print the current message as board text")
+	getCmd.Flags().Bool("render", false, "Draw the current message in the terminal")
+	getCmd.MarkFlagsMutuallyExclusive("layout", "id", "characters", "since", "text", "render")
 
 	setTransitionCmd := &cobra.Command{
 		Use:   "set-transition",
@@ -135,14 +275,54 @@ func NewRootCmd(stdin io.Reader, stdout, stderr io.Writer) *cobra.Command {
 		},
 	}
 
-	cmd.AddCommand(sendRawCmd, sendCmd, formatCmd, clearCmd, getCmd, setTransitionCmd, getTransitionCmd)
+	enableLocalCmd := &cobra.Command{
+		Use:   "enable-local-api [enablement-token|-]",
+		Short: "Exchange a Local API enablement token for a Local API key",
+		Args:  maxArgsWithHelp(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEnableLocalAPI(cmd, stdin, stdout, stderr, opts, args)
+		},
+	}
+
+	decodeCmd := &cobra.Command{
+		Use:   "decode [characters-json|-]",
+		Short: "Print a characters JSON array as board text",
+		Args:  maxArgsWithHelp(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDecode(cmd, stdin, stdout, opts, args)
+		},
+	}
+	decodeCmd.Flags().StringVarP(&opts.model, flagModel, "m", "", "Character table: flagship or note (default: inferred from dimensions)")
+
+	cmd.AddCommand(sendRawCmd, sendCmd, formatCmd, clearCmd, getCmd, setTransitionCmd, getTransitionCmd, enableLocalCmd, decodeCmd,
+		newPreviewCmd(stdin, stdout, stderr, opts),
+		newRenderCmd(stdin, stdout, stderr, opts),
+		newAnimateCmd(stdin, stdout, stderr, opts),
+		newSimulateCmd(stdin, stdout, stderr, opts),
+		newDiffCmd(stdin, stdout, stderr, opts),
+		newSnapshotCmd(stdin, stdout, stderr, opts),
+		newHistoryCmd(stdout, opts),
+		newUndoCmd(stdout, stderr, opts),
+		newRevertCmd(stderr, opts),
+		newWatchCmd(stdout, stderr, opts),
+		newDaemonCmd(stdout, stderr, opts),
+		newScheduleCmd(stdout, opts),
+		newPlaylistCmd(stdout, stderr, opts),
+		newPolicyCmd(stdout, opts),
+		newEnqueueCmd(stdin, stdout, stderr, opts),
+		newQueueCmd(stdout, opts),
+		newCountdownCmd(stdout, stderr, opts),
+		newTimerCmd(stdout, stderr, opts),
+		newStopwatchCmd(stdout, stderr, opts),
+		newClockCmd(stdout, stderr, opts),
+	)
 
 	return cmd
 }
 
 func runSendRaw(cmd *cobra.Command, stdin io.Reader, stdout, stderr io.Writer, opts *options, args []string) error {
 	ctx := cmd.Context()
-	client, err := buildClient(stderr, opts)
+	client, err := buildBoard(stderr, opts)
 	if err != nil {
 		return err
 	}
@@ -151,93 +331,291 @@ func runSendRaw(cmd *cobra.Command, stdin io.Reader, stdout, stderr io.Writer, o
 	if err != nil {
 		return err
 	}
-	return sendRawResolved(ctx, cmd, client, resolved)
+	return sendRawResolved(ctx, cmd, stderr, opts, client, resolved)
 }
 
 func runSend(cmd *cobra.Command, stdin io.Reader, stdout, stderr io.Writer, opts *options, args []string, formatOnly bool) error {
 	ctx := cmd.Context()
-	client, err := buildClient(stderr, opts)
-	if err != nil {
-		return err
+	// Formatting alone needs no board credentials.
+	client := opts.board
+	if !formatOnly {
+		var err error
+		if client, err = buildBoard(stderr, opts); err != nil {
+			return err
+		}
 	}
 
 	resolved, err := resolveCommandInput(cmd, stdin, args, "message")
 	if err != nil {
 		return err
 	}
-	if looksLikeRawCharactersJSON(resolved) {
-		return sendRawResolved(ctx, cmd, client, resolved)
+	formatter, err := buildFormatter(stderr, opts, client)
+	if err != nil {
+		return err
 	}
-	model, err := resolveModel(opts.model)
+	characters, model, err := renderInput(cmd, formatter, opts, resolved)
 	if err != nil {
-		return usageError(cmd, err)
+		return err
+	}
+	if formatOnly {
+		return writeCharacters(stdout, opts, characters, model)
+	}
+	if opts.ifChanged != "" {
+		unchanged, err := boardShows(ctx, opts, client, characters)
+		if err != nil {
+			return err
+		}
+		if unchanged {
+			return writeSendStatus(stdout, sendStatusUnchanged)
+		}
+	}
+	var previous *vestaboard.BoardState
+	if opts.sendFor > 0 {
+		if previous, err = client.GetCurrentState(ctx); err != nil {
+			return err
+		}
+	}
+	sent := delivery{Command: commandName(cmd), Source: resolved, Model: model, Characters: characters}
+	// A temporary message is not worth holding for quiet hours to end.
+	send := deliverWaiting
+	if opts.sendFor > 0 {
+		send = deliver
+	}
+	if err := send(ctx, stderr, opts, client, sent); err != nil {
+		return err
+	}
+	if opts.ifChanged != "" {
+		if err := writeSendStatus(stdout, sendStatusSent); err != nil {
+			return err
+		}
+	}
+	if opts.sendFor > 0 {
+		return holdTemporary(cmd, stdout, stderr, opts, client, newTemporaryRevert(opts, previous, characters))
 	}
-	align, err := resolveAlign(opts.align)
+	return nil
+}
+
+// renderInput uses a raw characters matrix as is and renders anything else
+// as a template.
+func renderInput(cmd *cobra.Command, formatter vestaboard.Formatter, opts *options, resolved string) ([][]int, string, error) {
+	style := messageStyle{Model: opts.model, Align: opts.align, Justify: opts.justify}
+	if err := style.validate(resolved); err != nil {
+		return nil, "", usageError(cmd, err)
+	}
+	return style.render(cmd.Context(), formatter, resolved)
+}
+
+type messageStyle struct {
+	Model   string
+	Align   string
+	Justify string
+}
+
+func (s messageStyle) validate(input string) error {
+	if looksLikeRawCharactersJSON(input) {
+		characters, err := parseCharacters(input)
+		if err != nil {
+			return fmt.Errorf("raw input must be a JSON array of arrays of integers: %w", err)
+		}
+		_, err = codecFor(s.Model, characters)
+		return err
+	}
+	if _, err := resolveModel(s.Model); err != nil {
+		return err
+	}
+	if _, err := resolveAlign(s.Align); err != nil {
+		return err
+	}
+	_, err := resolveJustify(s.Justify)
+	return err
+}
+
+func (s messageStyle) render(ctx context.Context, formatter vestaboard.Formatter, input string) ([][]int, string, error) {
+	if err := s.validate(input); err != nil {
+		return nil, "", err
+	}
+	if looksLikeRawCharactersJSON(input) {
+		characters, _ := parseCharacters(input)
+		table, _ := codecFor(s.Model, characters)
+		return characters, table.Model(), nil
+	}
+	model, _ := resolveModel(s.Model)
+	align, _ := resolveAlign(s.Align)
+	justify, _ := resolveJustify(s.Justify)
+
+	input = decodeEscapes(input)
+	input = substituteTemplateCharacterAliases(input)
+	characters, err := formatter.FormatMessage(ctx, input, model, align, justify)
 	if err != nil {
-		return usageError(cmd, err)
+		return nil, "", err
+	}
+	return characters, model, nil
+}
+
+func writeCharacters(stdout io.Writer, opts *options, characters [][]int, model string) error {
+	switch {
+	case opts.render:
+		return renderPreview(stdout, opts, characters, model, nil)
+	case opts.text:
+		if _, err := fmt.Fprintln(stdout, codec.ForModel(model).Decode(characters)); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+		return nil
 	}
-	justify, err := resolveJustify(opts.justify)
+	out, err := json.Marshal(characters)
 	if err != nil {
-		return usageError(cmd, err)
+		return fmt.Errorf("encode formatted output: %w", err)
+	}
+	if _, err := fmt.Fprintln(stdout, string(out)); err != nil {
+		return fmt.Errorf("write output: %w", err)
 	}
+	return nil
+}
 
-	resolved = decodeEscapes(resolved)
-	resolved = substituteTemplateCharacterAliases(resolved)
-	characters, err := client.FormatMessage(ctx, resolved, model, align, justify)
+func sendRawResolved(ctx context.Context, cmd *cobra.Command, stderr io.Writer, opts *options, client vestaboard.Board, resolved string) error {
+	characters, err := parseCharacters(resolved)
+	if err != nil {
+		return usageError(cmd, fmt.Errorf("raw input must be a JSON array of arrays of integers: %w", err))
+	}
+	return deliverWaiting(ctx, stderr, opts, client, delivery{Command: commandName(cmd), Source: resolved, Characters: characters})
+}
+
+// rawStateReader lets plain get print a response vbcli cannot parse.
+type rawStateReader interface {
+	GetCurrent(ctx context.Context) ([]byte, error)
+}
+
+func runGet(cmd *cobra.Command, stdout, stderr io.Writer, opts *options, projection string) error {
+	ctx := cmd.Context()
+	client, err := buildBoard(stderr, opts)
 	if err != nil {
 		return err
 	}
-	if formatOnly {
-		out, err := json.Marshal(characters)
+
+	if reader, ok := client.(rawStateReader); ok && projection == "" {
+		body, err := reader.GetCurrent(ctx)
 		if err != nil {
-			return fmt.Errorf("encode formatted output: %w", err)
+			return err
 		}
-		if _, err := fmt.Fprintln(stdout, string(out)); err != nil {
+		if _, err := fmt.Fprintln(stdout, string(body)); err != nil {
 			return fmt.Errorf("write output: %w", err)
 		}
 		return nil
 	}
-	if err := client.SendCharacters(ctx, characters); err != nil {
+
+	state, err := client.GetCurrentState(ctx)
+	if err != nil {
+		return err
+	}
+	if projection == "render" {
+		return renderPreview(stdout, opts, state.Layout, state.Model, nil)
+	}
+	out, err := projectBoardState(state, projection, time.Now())
+	if err != nil {
 		return err
 	}
+	if _, err := fmt.Fprintln(stdout, out); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
 	return nil
 }
 
-func sendRawResolved(ctx context.Context, cmd *cobra.Command, client *vestaboard.Client, resolved string) error {
+func resolveGetProjection(cmd *cobra.Command) (string, error) {
+	for _, name := range []string{"layout", "id", "characters", "since", "text", "render"} {
+		enabled, err := cmd.Flags().GetBool(name)
+		if err != nil {
+			return "", err
+		}
+		if enabled {
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+func projectBoardState(state *vestaboard.BoardState, projection string, now time.Time) (string, error) {
+	switch projection {
+	case "":
+		return string(state.Raw), nil
+	case "layout":
+		// The Cloud API reports the layout as a JSON string; print it as
+		// received. Other backends only have the decoded matrix.
+		if layout, err := extractLayout(state.Raw); err == nil {
+			return layout, nil
+		}
+		return projectBoardState(state, "characters", now)
+	case "id":
+		if state.ID == "" {
+			return "", errors.New("currentMessage.id not found")
+		}
+		return state.ID, nil
+	case "characters":
+		out, err := json.Marshal(state.Layout)
+		if err != nil {
+			return "", fmt.Errorf("encode characters: %w", err)
+		}
+		return string(out), nil
+	case "since":
+		if state.CreatedAt.IsZero() {
+			return "", errors.New("currentMessage creation time not reported by the API")
+		}
+		return now.Sub(state.CreatedAt).Round(time.Second).String(), nil
+	case "text":
+		return codec.ForModel(state.Model).Decode(state.Layout), nil
+	default:
+		return "", fmt.Errorf("unknown projection %q", projection)
+	}
+}
+
+func runDecode(cmd *cobra.Command, stdin io.Reader, stdout io.Writer, opts *options, args []string) error {
+	resolved, err := resolveCommandInput(cmd, stdin, args, "characters-json")
+	if err != nil {
+		return err
+	}
 	characters, err := parseCharacters(resolved)
 	if err != nil {
-		return usageError(cmd, fmt.Errorf("raw input must be a JSON array of arrays of integers: %w", err))
+		return usageError(cmd, fmt.Errorf("input must be a JSON array of arrays of integers: %w", err))
 	}
-	if err := client.SendCharacters(ctx, characters); err != nil {
-		return err
+	table, err := codecFor(opts.model, characters)
+	if err != nil {
+		return usageError(cmd, err)
+	}
+	if _, err := fmt.Fprintln(stdout, table.Decode(characters)); err != nil {
+		return fmt.Errorf("write output: %w", err)
 	}
 	return nil
 }
 
-func runGet(cmd *cobra.Command, stdout, stderr io.Writer, opts *options, layoutOnly bool) error {
+// codecFor tries --model, then the matrix dimensions, then VESTABOARD_MODEL.
+func codecFor(modelFlag string, characters [][]int) (*codec.Codec, error) {
+	if strings.TrimSpace(modelFlag) == "" {
+		if model := vestaboard.InferModel(vestaboard.Dimensions(characters)); model != "" {
+			return codec.ForModel(model), nil
+		}
+	}
+	model, err := resolveModel(modelFlag)
+	if err != nil {
+		return nil, err
+	}
+	return codec.ForModel(model), nil
+}
+
+func runEnableLocalAPI(cmd *cobra.Command, stdin io.Reader, stdout, stderr io.Writer, opts *options, args []string) error {
 	ctx := cmd.Context()
-	client, err := buildClient(stderr, opts)
+	clientOptions, err := buildClientOptions(stderr, opts)
 	if err != nil {
 		return err
 	}
-
-	stateJSON, err := client.GetCurrent(ctx)
+	token, err := resolveCommandInput(cmd, stdin, args, "enablement-token")
 	if err != nil {
 		return err
 	}
 
-	if layoutOnly {
-		layout, err := extractLayout(stateJSON)
-		if err != nil {
-			return err
-		}
-		if _, err := fmt.Fprintln(stdout, layout); err != nil {
-			return fmt.Errorf("write output: %w", err)
-		}
-		return nil
+	apiKey, err := vbapi.EnableLocalAPI(ctx, resolveSetting(opts.host, envHost), token, clientOptions...)
+	if err != nil {
+		return err
 	}
-
-	if _, err := fmt.Fprintln(stdout, string(stateJSON)); err != nil {
+	if _, err := fmt.Fprintln(stdout, apiKey); err != nil {
 		return fmt.Errorf("write output: %w", err)
 	}
 	return nil
@@ -245,7 +623,7 @@ func runGet(cmd *cobra.Command, stdout, stderr io.Writer, opts *options, layoutO
 
 func runSetTransition(cmd *cobra.Command, stderr io.Writer, opts *options) error {
 	ctx := cmd.Context()
-	client, err := buildClient(stderr, opts)
+	client, err := buildBoard(stderr, opts)
 	if err != nil {
 		return err
 	}
@@ -264,7 +642,7 @@ func runSetTransition(cmd *cobra.Command, stderr io.Writer, opts *options) error
 
 func runGetTransition(cmd *cobra.Command, stdout, stderr io.Writer, opts *options) error {
 	ctx := cmd.Context()
-	client, err := buildClient(stderr, opts)
+	client, err := buildBoard(stderr, opts)
 	if err != nil {
 		return err
 	}
@@ -306,9 +684,119 @@ func prettyPrintJSON(raw []byte) ([]byte, error) {
 	return out.Bytes(), nil
 }
 
-func buildClient(stderr io.Writer, opts *options) (*vestaboard.Client, error) {
+func buildBoard(stderr io.Writer, opts *options) (vestaboard.Board, error) {
+	if opts.board != nil {
+		return opts.board, nil
+	}
+	backend, err := resolveBackend(opts.backend)
+	if err != nil {
+		return nil, err
+	}
+	clientOptions, err := buildClientOptions(stderr, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if backend == backendLocal {
+		host := resolveSetting(opts.host, envHost)
+		if host == "" {
+			return nil, fmt.Errorf("--host (or %s) is required for the local backend", envHost)
+		}
+		apiKey := strings.TrimSpace(os.Getenv(envLocalAPIKey))
+		return vbapi.NewLocalClient(host, apiKey, clientOptions...)
+	}
+
+	if apiURL := resolveSetting(opts.apiURL, envAPIURL); apiURL != "" {
+		clientOptions = append(clientOptions, vbapi.WithBaseURL(apiURL))
+	}
 	token := strings.TrimSpace(os.Getenv(envVestaboardToken))
-	return vestaboard.NewClient(token, vestaboard.WithVerboseLogging(opts.verbose, stderr))
+	return vbapi.NewClient(token, clientOptions...)
+}
+
+func buildFormatter(stderr io.Writer, opts *options, board vestaboard.Board) (vestaboard.Formatter, error) {
+	if opts.formatter != nil {
+		return opts.formatter, nil
+	}
+	renderer, err := resolveRenderer(opts.renderer)
+	if err != nil {
+		return nil, err
+	}
+	if renderer == rendererLocal {
+		return vbml.Renderer{}, nil
+	}
+	if formatter, ok := board.(vestaboard.Formatter); ok {
+		return formatter, nil
+	}
+	clientOptions, err := buildClientOptions(stderr, opts)
+	if err != nil {
+		return nil, err
+	}
+	return vbapi.NewVBMLClient(clientOptions...)
+}
+
+func buildClientOptions(stderr io.Writer, opts *options) ([]vbapi.Option, error) {
+	if opts.retries < 0 {
+		return nil, fmt.Errorf("invalid --retries %d (expected 0 or more)", opts.retries)
+	}
+	timeout, err := resolveTimeout(opts.timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	clientOptions := []vbapi.Option{
+		vbapi.WithVerboseLogging(opts.verbose, stderr),
+		vbapi.WithUserAgent(userAgent),
+		vbapi.WithRetryPolicy(vbapi.RetryPolicy{
+			MaxAttempts: opts.retries + 1,
+			MaxDelay:    opts.retryMaxWait,
+		}),
+	}
+	limit := vbapi.LimiterConfig{
+		MinInterval: opts.minInterval,
+		FailFast:    opts.failFast,
+		Coalesce:    opts.coalesce,
+		Clock:       opts.clock,
+	}
+	if opts.minInterval > 0 {
+		store, err := openState(opts)
+		if err != nil {
+			return nil, err
+		}
+		limit.Shared = &sharedSendLimit{store: store, profile: boardProfile(opts)}
+	}
+	clientOptions = append(clientOptions, vbapi.WithLimiter(vbapi.NewLimiter(limit)))
+	if timeout > 0 {
+		clientOptions = append(clientOptions, vbapi.WithTimeout(timeout))
+	}
+	if vbmlURL := resolveSetting(opts.vbmlURL, envVBMLURL); vbmlURL != "" {
+		clientOptions = append(clientOptions, vbapi.WithVBMLURL(vbmlURL))
+	}
+	return clientOptions, nil
+}
+
+func resolveSetting(value, envName string) string {
+	if trimmed := strings.TrimSpace(value); trimmed != "" {
+		return trimmed
+	}
+	return strings.TrimSpace(os.Getenv(envName))
+}
+
+func resolveTimeout(value time.Duration) (time.Duration, error) {
+	if value < 0 {
+		return 0, fmt.Errorf("invalid --timeout %s (expected a positive duration)", value)
+	}
+	if value > 0 {
+		return value, nil
+	}
+	raw := strings.TrimSpace(os.Getenv(envTimeout))
+	if raw == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(raw)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid %s %q (expected a positive duration such as 30s)", envTimeout, raw)
+	}
+	return timeout, nil
 }
 
 func decodeEscapes(input string) string {
@@ -335,6 +823,42 @@ func resolveModel(value string) (string, error) {
 	}
 }
 
+const (
+	backendCloud = "cloud"
+	backendLocal = "local"
+)
+
+func resolveBackend(value string) (string, error) {
+	backend := strings.ToLower(resolveSetting(value, envBackend))
+	if backend == "" {
+		backend = backendCloud
+	}
+	switch backend {
+	case backendCloud, backendLocal:
+		return backend, nil
+	default:
+		return "", fmt.Errorf("invalid --backend %q (expected \"cloud\" or \"local\")", backend)
+	}
+}
+
+const (
+	rendererRemote = "remote"
+	rendererLocal  = "local"
+)
+
+func resolveRenderer(value string) (string, error) {
+	renderer := strings.ToLower(resolveSetting(value, envRenderer))
+	if renderer == "" {
+		renderer = rendererRemote
+	}
+	switch renderer {
+	case rendererRemote, rendererLocal:
+		return renderer, nil
+	default:
+		return "", fmt.Errorf("invalid --renderer %q (expected \"local\" or \"remote\")", renderer)
+	}
+}
+
 func resolveAlign(value string) (string, error) {
 	align := strings.ToLower(strings.TrimSpace(value))
 	if align == "" {
@@ -426,6 +950,20 @@ func maxArgsWithHelp(n int) cobra.PositionalArgs {
 	}
 }
 
+func commandName(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+}
+
+func rangeArgsWithHelp(minArgs, maxArgs int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) >= minArgs && len(args) <= maxArgs {
+			return nil
+		}
+		_ = cmd.Help()
+		return fmt.Errorf("accepts between %d and %d arg(s), received %d", minArgs, maxArgs, len(args))
+	}
+}
+
 func resolveCommandInput(cmd *cobra.Command, stdin io.Reader, args []string, argName string) (string, error) {
 	if len(args) == 1 {
 		value, err := resolveValue(stdin, args[0])
@@ -458,6 +996,18 @@ func stdinIsTerminal(stdin io.Reader) bool {
 	return (info.Mode() & os.ModeCharDevice) != 0
 }
 
+func sleepContext(ctx context.Context, clock vbapi.Clock, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-clock.After(d):
+		return nil
+	}
+}
+
 func resolveValue(stdin io.Reader, value string) (string, error) {
 	if value != "-" {
 		return value, nil
@@ -2,11 +2,20 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	vbapi "vbcli/internal/vestaboard"
+	"vbcli/vestaboard"
 )
 
 func TestResolveValue(t *testing.T) {
@@ -362,3 +371,316 @@ func TestSubcommandsExist(t *testing.T) {
 		}
 	}
 }
+
+func TestProjectBoardState(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	state := &vestaboard.BoardState{ID: "abc", CreatedAt: created, Layout: [][]int{{1, 2}, {3, 4}}}
+
+	if got, err := projectBoardState(state, "id", created); err != nil || got != "abc" {
+		t.Fatalf("id projection = %q, err %v", got, err)
+	}
+	if got, err := projectBoardState(state, "characters", created); err != nil || got != "[[1,2],[3,4]]" {
+		t.Fatalf("characters projection = %q, err %v", got, err)
+	}
+	if got, err := projectBoardState(state, "since", created.Add(90*time.Second)); err != nil || got != "1m30s" {
+		t.Fatalf("since projection = %q, err %v", got, err)
+	}
+	if _, err := projectBoardState(&vestaboard.BoardState{}, "since", created); err == nil {
+		t.Fatal("expected error when creation time is missing")
+	}
+}
+
+func TestResolveSettingFlagOverridesEnv(t *testing.T) {
+	t.Setenv(envAPIURL, "https://env.example.com")
+	if got := resolveSetting("", envAPIURL); got != "https://env.example.com" {
+		t.Fatalf("got %q, want env value", got)
+	}
+	if got := resolveSetting("https://flag.example.com", envAPIURL); got != "https://flag.example.com" {
+		t.Fatalf("got %q, want flag value", got)
+	}
+}
+
+func TestResolveTimeout(t *testing.T) {
+	t.Setenv(envTimeout, "45s")
+	if got, err := resolveTimeout(0); err != nil || got != 45*time.Second {
+		t.Fatalf("got %v, err %v; want 45s from env", got, err)
+	}
+	if got, err := resolveTimeout(5 * time.Second); err != nil || got != 5*time.Second {
+		t.Fatalf("got %v, err %v; want flag value", got, err)
+	}
+
+	t.Setenv(envTimeout, "soon")
+	if _, err := resolveTimeout(0); err == nil {
+		t.Fatal("expected error for invalid env timeout")
+	}
+}
+
+func TestResolveBackend(t *testing.T) {
+	t.Setenv(envBackend, "")
+	if got, err := resolveBackend(""); err != nil || got != backendCloud {
+		t.Fatalf("default backend = %q, err %v", got, err)
+	}
+	if got, err := resolveBackend("LOCAL"); err != nil || got != backendLocal {
+		t.Fatalf("backend = %q, err %v", got, err)
+	}
+	if _, err := resolveBackend("serial"); err == nil {
+		t.Fatal("expected error for unknown backend")
+	}
+
+	t.Setenv(envBackend, "local")
+	if got, err := resolveBackend(""); err != nil || got != backendLocal {
+		t.Fatalf("env backend = %q, err %v", got, err)
+	}
+}
+
+func TestBuildBoardLocalRequiresHost(t *testing.T) {
+	t.Setenv(envHost, "")
+	t.Setenv(envLocalAPIKey, "local-key")
+
+	opts := &options{backend: backendLocal}
+	if _, err := buildBoard(&bytes.Buffer{}, opts); err == nil {
+		t.Fatal("expected error without --host")
+	}
+
+	opts.host = "vestaboard.local"
+	got, err := buildBoard(&bytes.Buffer{}, opts)
+	if err != nil {
+		t.Fatalf("build board: %v", err)
+	}
+	if _, ok := got.(*vbapi.LocalClient); !ok {
+		t.Fatalf("got %T, want *vbapi.LocalClient", got)
+	}
+}
+
+func TestProjectBoardStateLayoutFallsBackToCharacters(t *testing.T) {
+	t.Parallel()
+
+	cloud := &vestaboard.BoardState{Raw: []byte(`{"currentMessage":{"layout":"[[1, 2]]"}}`), Layout: [][]int{{1, 2}}}
+	if got, err := projectBoardState(cloud, "layout", time.Time{}); err != nil || got != "[[1, 2]]" {
+		t.Fatalf("cloud layout = %q, err %v", got, err)
+	}
+
+	local := &vestaboard.BoardState{Raw: []byte(`[[1,2]]`), Layout: [][]int{{1, 2}}}
+	if got, err := projectBoardState(local, "layout", time.Time{}); err != nil || got != "[[1,2]]" {
+		t.Fatalf("local layout = %q, err %v", got, err)
+	}
+}
+
+type stubFormatter struct {
+	characters [][]int
+	gotMessage string
+}
+
+func (f *stubFormatter) FormatMessage(_ context.Context, message, _, _, _ string) ([][]int, error) {
+	f.gotMessage = message
+	return f.characters, nil
+}
+
+// instantClock is a vbapi.Clock whose timers fire immediately,
+// advancing its time by the requested duration.
+type instantClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	slept []time.Duration
+}
+
+func newInstantClock(now time.Time) *instantClock {
+	return &instantClock{now: now}
+}
+
+func (c *instantClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *instantClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.slept = append(c.slept, d)
+	fired := make(chan time.Time, 1)
+	fired <- c.now
+	return fired
+}
+
+func (c *instantClock) Slept() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.slept...)
+}
+
+// emptyConfig returns an empty config file, so tests never read the
+// developer's own quiet hours.
+func emptyConfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func runRoot(t *testing.T, stdin string, rootOptions []Option, args ...string) (string, error) {
+	t.Helper()
+	var stdout bytes.Buffer
+	rootOptions = append([]Option{WithStateDir(t.TempDir()), WithConfigFile(emptyConfig(t))}, rootOptions...)
+	root := NewRootCmd(strings.NewReader(stdin), &stdout, &bytes.Buffer{}, rootOptions...)
+	root.SetArgs(args)
+	err := root.Execute()
+	return stdout.String(), err
+}
+
+func TestSendRawWithFakeBoard(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard(nil)
+	if _, err := runRoot(t, "", []Option{WithBoard(fake)}, "send-raw", "[[1,2],[3,4]]"); err != nil {
+		t.Fatalf("send-raw: %v", err)
+	}
+	if len(fake.Sent) != 1 || fake.Sent[0][1][0] != 3 {
+		t.Fatalf("unexpected sends: %#v", fake.Sent)
+	}
+}
+
+func TestSendWithFakeBoardAndFormatter(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard(nil)
+	formatter := &stubFormatter{characters: [][]int{{8, 9}}}
+	if _, err := runRoot(t, "", []Option{WithBoard(fake), WithFormatter(formatter)}, "send", `hi {green}\n`); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if formatter.gotMessage != "hi {66}\n" {
+		t.Fatalf("formatter got %q", formatter.gotMessage)
+	}
+	if len(fake.Sent) != 1 || fake.Sent[0][0][1] != 9 {
+		t.Fatalf("unexpected sends: %#v", fake.Sent)
+	}
+}
+
+func TestGetWithFakeBoard(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{5, 6}})
+	out, err := runRoot(t, "", []Option{WithBoard(fake)}, "get", "--characters")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if strings.TrimSpace(out) != "[[5,6]]" {
+		t.Fatalf("got %q", out)
+	}
+}
+
+func TestGetPrintsUnparsedBody(t *testing.T) {
+	t.Setenv(envVestaboardToken, "abc123")
+	t.Setenv(envBackend, "")
+
+	body := `{"currentMessage":{"layout":"not a layout"}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	out, err := runRoot(t, "", nil, "--api-url", server.URL, "get")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if out != body+"\n" {
+		t.Fatalf("got %q, want the body as received", out)
+	}
+	if _, err := runRoot(t, "", nil, "--api-url", server.URL, "get", "--characters"); err == nil {
+		t.Fatal("get --characters should fail on an unparseable layout")
+	}
+}
+
+func TestTransitionsWithFakeBoard(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard(nil)
+	if _, err := runRoot(t, "", []Option{WithBoard(fake)}, "set-transition", "--type", "drift", "--speed", "gentle"); err != nil {
+		t.Fatalf("set-transition: %v", err)
+	}
+	out, err := runRoot(t, "", []Option{WithBoard(fake)}, "get-transition")
+	if err != nil {
+		t.Fatalf("get-transition: %v", err)
+	}
+	if !strings.Contains(out, `"transition": "drift"`) {
+		t.Fatalf("unexpected output: %s", out)
+	}
+}
+
+func TestResolveRenderer(t *testing.T) {
+	t.Setenv(envRenderer, "")
+	if got, err := resolveRenderer(""); err != nil || got != rendererRemote {
+		t.Fatalf("default renderer = %q, err %v", got, err)
+	}
+	t.Setenv(envRenderer, "local")
+	if got, err := resolveRenderer(""); err != nil || got != rendererLocal {
+		t.Fatalf("env renderer = %q, err %v", got, err)
+	}
+	if got, err := resolveRenderer("Remote"); err != nil || got != rendererRemote {
+		t.Fatalf("flag renderer = %q, err %v", got, err)
+	}
+	if _, err := resolveRenderer("offline"); err == nil {
+		t.Fatal("expected error for unknown renderer")
+	}
+}
+
+func TestFormatWithLocalRenderer(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard(nil)
+	out, err := runRoot(t, "", []Option{WithBoard(fake)}, "--renderer", "local", "format", "-m", "note", "-a", "top", "-j", "left", "hi {red}")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	if !strings.HasPrefix(out, "[[8,9,0,63,0,") {
+		t.Fatalf("unexpected output: %s", out)
+	}
+}
+
+func TestFormatWithLocalRendererNeedsNoToken(t *testing.T) {
+	t.Setenv(envVestaboardToken, "")
+	t.Setenv(envBackend, "")
+
+	out, err := runRoot(t, "", nil, "--renderer", "local", "format", "-m", "note", "hello")
+	if err != nil {
+		t.Fatalf("format without a token: %v", err)
+	}
+	if !strings.HasPrefix(out, "[[") {
+		t.Fatalf("unexpected output: %s", out)
+	}
+	if _, err := runRoot(t, "", nil, "--renderer", "local", "send", "hello"); err == nil {
+		t.Fatal("send without a token should still fail")
+	}
+}
+
+func TestDecodeCommandInfersNote(t *testing.T) {
+	t.Parallel()
+
+	row := "[8,9,0,62,0,0,0,0,0,0,0,0,0,0,0]"
+	blank := "[0,0,0,0,0,0,0,0,0,0,0,0,0,0,0]"
+	out, err := runRoot(t, "", nil, "decode", "["+row+","+blank+","+blank+"]")
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !strings.HasPrefix(out, "HI ❤") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+}
+
+func TestGetTextWithFakeBoard(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{8, 9, 63}})
+	out, err := runRoot(t, "", []Option{WithBoard(fake)}, "get", "--text")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if strings.TrimSpace(out) != "HI{red}" {
+		t.Fatalf("got %q", out)
+	}
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vbcli/internal/schedule"
+)
+
+type scheduleNextOptions struct {
+	path  string
+	limit int
+	from  string
+	json  bool
+}
+
+type scheduledFiring struct {
+	Time     time.Time `json:"time"`
+	Entry    string    `json:"entry"`
+	Priority int       `json:"priority"`
+	Message  string    `json:"message"`
+	// SkippedFor names the higher-priority entry sent instead.
+	SkippedFor string `json:"skippedFor,omitempty"`
+}
+
+func newScheduleCmd(stdout io.Writer, opts *options) *cobra.Command {
+	scheduleCmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Inspect schedule files used by the daemon",
+		Args:  exactArgsWithHelp(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			_ = cmd.Help()
+			return errors.New("a subcommand is required: next")
+		},
+	}
+
+	var nextOpts scheduleNextOptions
+	nextCmd := &cobra.Command{
+		Use:   "next",
+		Short: "Print upcoming firings without sending anything",
+		Args:  exactArgsWithHelp(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runScheduleNext(cmd, stdout, opts, nextOpts)
+		},
+	}
+	nextCmd.Flags().StringVar(&nextOpts.path, "schedule", "", "Schedule file (YAML)")
+	nextCmd.Flags().IntVarP(&nextOpts.limit, "limit", "n", 10, "Number of firing times to print")
+	nextCmd.Flags().StringVar(&nextOpts.from, "from", "", "Start from this RFC 3339 time instead of now")
+	nextCmd.Flags().BoolVar(&nextOpts.json, "json", false, "Print firings as a JSON array")
+	_ = nextCmd.MarkFlagRequired("schedule")
+
+	scheduleCmd.AddCommand(nextCmd)
+	return scheduleCmd
+}
+
+func runScheduleNext(cmd *cobra.Command, stdout io.Writer, opts *options, nextOpts scheduleNextOptions) error {
+	if nextOpts.limit <= 0 {
+		return usageError(cmd, fmt.Errorf("invalid --limit %d", nextOpts.limit))
+	}
+	from := opts.clock.Now()
+	if nextOpts.from != "" {
+		parsed, err := time.Parse(time.RFC3339, nextOpts.from)
+		if err != nil {
+			return usageError(cmd, fmt.Errorf("invalid --from %q (expected an RFC 3339 time)", nextOpts.from))
+		}
+		from = parsed
+	}
+	sched, err := loadSchedule(nextOpts.path)
+	if err != nil {
+		return err
+	}
+
+	firings := []scheduledFiring{}
+	for _, slot := range sched.Upcoming(from, nextOpts.limit) {
+		for i, entry := range slot.Entries {
+			firing := scheduledFiring{
+				Time:     slot.Time,
+				Entry:    entry.Name,
+				Priority: entry.Priority,
+				Message:  entrySummary(entry),
+			}
+			if i > 0 {
+				firing.SkippedFor = slot.Entries[0].Name
+			}
+			firings = append(firings, firing)
+		}
+	}
+
+	if nextOpts.json {
+		out, err := json.Marshal(firings)
+		if err != nil {
+			return fmt.Errorf("encode firings: %w", err)
+		}
+		if _, err := fmt.Fprintln(stdout, string(out)); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tENTRY\tPRIORITY\tMESSAGE")
+	for _, firing := range firings {
+		message := firing.Message
+		if firing.SkippedFor != "" {
+			message = fmt.Sprintf("(skipped for %s)", firing.SkippedFor)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", firing.Time.Format(displayTimeFormat), firing.Entry, firing.Priority, message)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+// loadSchedule checks every entry the way send would, so mistakes surface
+// when the file is loaded rather than when an entry fires.
+func loadSchedule(path string) (*schedule.Schedule, error) {
+	sched, err := schedule.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range sched.Entries {
+		if err := entryStyle(entry).validate(entryInput(entry)); err != nil {
+			return nil, fmt.Errorf("%s: entry %q: %w", path, entry.Name, err)
+		}
+		if entry.Transition == nil {
+			continue
+		}
+		if _, err := resolveTransitionType(entry.Transition.Type); err != nil {
+			return nil, fmt.Errorf("%s: entry %q: transition: %w", path, entry.Name, err)
+		}
+		if _, err := resolveTransitionSpeed(entry.Transition.Speed); err != nil {
+			return nil, fmt.Errorf("%s: entry %q: transition: %w", path, entry.Name, err)
+		}
+	}
+	return sched, nil
+}
+
+func entryStyle(entry *schedule.Entry) messageStyle {
+	return messageStyle{Model: entry.Model, Align: entry.Align, Justify: entry.Justify}
+}
+
+func entryInput(entry *schedule.Entry) string {
+	if len(entry.Characters) == 0 {
+		return entry.Message
+	}
+	out, _ := json.Marshal(entry.Characters)
+	return string(out)
+}
+
+func entrySummary(entry *schedule.Entry) string {
+	if len(entry.Characters) > 0 {
+		table, err := codecFor(entry.Model, entry.Characters)
+		if err != nil {
+			return entryInput(entry)
+		}
+		return summarizeLayout(entry.Characters, table.Model())
+	}
+	return summarizeText(entry.Message)
+}
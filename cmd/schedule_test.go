@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const scheduleNextFile = `
+timezone: UTC
+entries:
+  - name: standup
+    cron: "0 9 * * mon-fri"
+    message: "Standup in the big room"
+    priority: 5
+  - name: drill
+    cron: "0 9 16 10 *"
+    characters: [[8, 9]]
+    priority: 10
+`
+
+func TestScheduleNext(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "schedule.yaml")
+	writeScheduleFile(t, path, scheduleNextFile)
+	clock := newInstantClock(time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC))
+
+	out, err := runRoot(t, "", []Option{WithClock(clock)}, "schedule", "next", "--schedule", path, "-n", "2")
+	if err != nil {
+		t.Fatalf("schedule next: %v", err)
+	}
+	want := `TIME                  ENTRY    PRIORITY  MESSAGE
+2026-10-16 09:00 UTC  drill    10        HI
+2026-10-16 09:00 UTC  standup  5         (skipped for drill)
+2026-10-19 09:00 UTC  standup  5         Standup in the big room
+`
+	if out != want {
+		t.Fatalf("output:\n%s\nwant:\n%s", out, want)
+	}
+	if len(clock.Slept()) != 0 {
+		t.Fatal("schedule next must not wait")
+	}
+}
+
+func TestScheduleNextJSONFrom(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "schedule.yaml")
+	writeScheduleFile(t, path, scheduleNextFile)
+	out, err := runRoot(t, "", nil, "schedule", "next", "--schedule", path, "--from", "2026-10-17T00:00:00Z", "-n", "1", "--json")
+	if err != nil {
+		t.Fatalf("schedule next: %v", err)
+	}
+	var firings []scheduledFiring
+	if err := json.Unmarshal([]byte(out), &firings); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(firings) != 1 || firings[0].Entry != "standup" || !firings[0].Time.Equal(time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected firings: %+v", firings)
+	}
+}
+
+func TestScheduleNextErrors(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "schedule.yaml")
+	writeScheduleFile(t, path, scheduleNextFile)
+	for _, args := range [][]string{
+		{"schedule", "next"},
+		{"schedule", "next", "--schedule", filepath.Join(t.TempDir(), "missing.yaml")},
+		{"schedule", "next", "--schedule", path, "-n", "0"},
+		{"schedule", "next", "--schedule", path, "--from", "tomorrow"},
+	} {
+		if _, err := runRoot(t, "", nil, args...); err == nil {
+			t.Fatalf("expected error for %s", strings.Join(args, " "))
+		}
+	}
+}
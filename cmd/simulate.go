@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"vbcli/internal/codec"
+	"vbcli/internal/flap"
+	"vbcli/internal/preview"
+	"vbcli/vestaboard"
+)
+
+const simulateFromCurrent = "current"
+
+func newSimulateCmd(stdin io.Reader, stdout, stderr io.Writer, opts *options) *cobra.Command {
+	var from, transitionType, transitionSpeed string
+	simulateCmd := &cobra.Command{
+		Use:   "simulate [message|characters-json|-]",
+		Short: "Animate a split-flap transition in the terminal without sending anything",
+		Long: `Animate a split-flap transition in the terminal without sending anything.
+
+The target is a message or characters matrix, as for send. The board starts
+blank unless --from gives a characters matrix or "current" for what the board
+shows now.`,
+		Args: maxArgsWithHelp(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSimulate(cmd, stdin, stdout, stderr, opts, args, from, transitionType, transitionSpeed)
+		},
+	}
+	simulateCmd.Flags().StringVar(&from, "from", "", `Starting layout: a characters matrix or "current"`)
+	simulateCmd.Flags().StringVar(&transitionType, "type", "classic", "Transition type: classic, wave, drift, curtain")
+	simulateCmd.Flags().StringVar(&transitionSpeed, "speed", "fast", "Transition speed: fast, gentle")
+	simulateCmd.Flags().StringVarP(&opts.model, flagModel, "m", "", "VBML model for the target: flagship or note")
+	simulateCmd.Flags().StringVarP(&opts.align, "align", "a", "center", "VBML align for the target: top, center, or bottom")
+	simulateCmd.Flags().StringVarP(&opts.justify, "justify", "j", "center", "VBML justify for the target: left, center, right, or justified")
+	return simulateCmd
+}
+
+func runSimulate(cmd *cobra.Command, stdin io.Reader, stdout, stderr io.Writer, opts *options, args []string, from, transitionType, transitionSpeed string) error {
+	transitionType, err := resolveTransitionType(transitionType)
+	if err != nil {
+		return usageError(cmd, err)
+	}
+	transitionSpeed, err = resolveTransitionSpeed(transitionSpeed)
+	if err != nil {
+		return usageError(cmd, err)
+	}
+	mode, err := preview.ParseMode(opts.color, stdout)
+	if err != nil {
+		return usageError(cmd, err)
+	}
+
+	resolved, err := resolveCommandInput(cmd, stdin, args, "message")
+	if err != nil {
+		return err
+	}
+	formatter, err := buildFormatter(stderr, opts, opts.board)
+	if err != nil {
+		return err
+	}
+	target, model, err := renderInput(cmd, formatter, opts, resolved)
+	if err != nil {
+		return err
+	}
+	start, err := resolveSimulateFrom(cmd, stderr, opts, from, target)
+	if err != nil {
+		return err
+	}
+
+	frames, err := flap.Transition(start, target, flap.Options{Type: transitionType, Speed: transitionSpeed, Seed: opts.clock.Now().UnixNano()})
+	if err != nil {
+		return err
+	}
+	return playFrames(cmd, stdout, opts, frames, preview.Options{Mode: mode, Codec: codec.ForModel(model)})
+}
+
+func resolveSimulateFrom(cmd *cobra.Command, stderr io.Writer, opts *options, from string, target [][]int) ([][]int, error) {
+	switch strings.TrimSpace(from) {
+	case "":
+		rows, columns := vestaboard.Dimensions(target)
+		return flap.Pad(nil, rows, columns), nil
+	case simulateFromCurrent:
+		board, err := buildBoard(stderr, opts)
+		if err != nil {
+			return nil, err
+		}
+		state, err := board.GetCurrentState(cmd.Context())
+		if err != nil {
+			return nil, err
+		}
+		return state.Layout, nil
+	}
+	if !looksLikeRawCharactersJSON(from) {
+		return nil, usageError(cmd, errors.New(`--from must be a characters matrix or "current"`))
+	}
+	characters, err := parseCharacters(from)
+	if err != nil {
+		return nil, usageError(cmd, fmt.Errorf("--from must be a JSON array of arrays of integers: %w", err))
+	}
+	return characters, nil
+}
+
+// playFrames redraws in place on a terminal and prints frames in sequence
+// otherwise.
+func playFrames(cmd *cobra.Command, stdout io.Writer, opts *options, frames []flap.Frame, previewOpts preview.Options) error {
+	inPlace := preview.IsTerminal(stdout)
+	height := 0
+	for i, frame := range frames {
+		var buf bytes.Buffer
+		if inPlace && height > 0 {
+			fmt.Fprintf(&buf, "\x1b[%dA", height)
+		}
+		var drawn bytes.Buffer
+		if err := preview.Render(&drawn, frame.Layout, previewOpts); err != nil {
+			return err
+		}
+		height = bytes.Count(drawn.Bytes(), []byte("\n"))
+		buf.Write(drawn.Bytes())
+		if _, err := stdout.Write(buf.Bytes()); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+		if i < len(frames)-1 {
+			if err := sleepContext(cmd.Context(), opts.clock, frame.Duration); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"vbcli/vestaboard"
+)
+
+func TestSimulatePrintsEveryFrame(t *testing.T) {
+	t.Parallel()
+
+	clock := newInstantClock(time.Unix(0, 0))
+	out, err := runRoot(t, "", []Option{WithClock(clock)}, "--color", "none", "simulate", "[[2]]")
+	if err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	want := "+---+\n|   |\n+---+\n" + "+---+\n| A |\n+---+\n" + "+---+\n| B |\n+---+\n"
+	if out != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", out, want)
+	}
+	slept := clock.Slept()
+	if len(slept) != 2 || slept[0] != 40*time.Millisecond {
+		t.Fatalf("unexpected sleeps: %v", slept)
+	}
+}
+
+func TestSimulateFromCurrentBoard(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{1, 5}})
+	clock := newInstantClock(time.Unix(0, 0))
+	out, err := runRoot(t, "", []Option{WithBoard(fake), WithClock(clock)},
+		"--color", "none", "simulate", "--from", "current", "--type", "wave", "--speed", "gentle", "[[2,5]]")
+	if err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	if !strings.HasPrefix(out, "+------+\n| A  E |\n") || !strings.HasSuffix(out, "| B  E |\n+------+\n") {
+		t.Fatalf("unexpected frames:\n%s", out)
+	}
+	if slept := clock.Slept(); len(slept) != 1 || slept[0] != 90*time.Millisecond {
+		t.Fatalf("unexpected sleeps: %v", slept)
+	}
+	if len(fake.Sent) != 0 {
+		t.Fatal("simulate must not send")
+	}
+}
+
+func TestSimulateRejectsBadFlags(t *testing.T) {
+	t.Parallel()
+
+	tests := [][]string{
+		{"simulate", "--type", "spin", "[[1]]"},
+		{"simulate", "--speed", "slow", "[[1]]"},
+		{"simulate", "--from", "yesterday", "[[1]]"},
+	}
+	for _, args := range tests {
+		if _, err := runRoot(t, "", []Option{WithClock(newInstantClock(time.Unix(0, 0)))}, args...); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+}
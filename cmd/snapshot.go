@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	snapshotDir     = "snapshots"
+	snapshotVersion = 1
+)
+
+var snapshotName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// snapshot is the same JSON on disk and in exports.
+type snapshot struct {
+	Version   int       `json:"version"`
+	Name      string    `json:"name"`
+	SavedAt   time.Time `json:"savedAt"`
+	MessageID string    `json:"messageId,omitempty"`
+	Model     string    `json:"model,omitempty"`
+	Profile   string    `json:"profile,omitempty"`
+	Layout    [][]int   `json:"layout"`
+}
+
+func newSnapshotCmd(stdin io.Reader, stdout, stderr io.Writer, opts *options) *cobra.Command {
+	snapshotCmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save board layouts locally and restore them later",
+		Args:  exactArgsWithHelp(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			_ = cmd.Help()
+			return errors.New("a subcommand is required: save, list, show, restore, delete, export, or import")
+		},
+	}
+
+	var force bool
+	saveCmd := &cobra.Command{
+		Use:   "save <name>",
+		Short: "Save what the board shows now",
+		Args:  exactArgsWithHelp(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSnapshotSave(cmd, stdout, stderr, opts, args[0], force)
+		},
+	}
+	saveCmd.Flags().BoolVarP(&force, "force", "f", false, "Overwrite an existing snapshot")
+
+	var listJSON bool
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List saved snapshots",
+		Args:  exactArgsWithHelp(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runSnapshotList(stdout, opts, listJSON)
+		},
+	}
+	listCmd.Flags().BoolVar(&listJSON, "json", false, "Print snapshots as a JSON array")
+
+	showCmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Print a saved snapshot as JSON, board text or a terminal preview",
+		Args:  exactArgsWithHelp(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSnapshotShow(cmd, stdout, opts, args[0])
+		},
+	}
+	showCmd.Flags().BoolVar(&opts.text, "text", false, "Print the snapshot as board text")
+	showCmd.Flags().BoolVar(&opts.render, "render", false, "Draw the snapshot in the terminal")
+	showCmd.MarkFlagsMutuallyExclusive("text", "render")
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore <name>",
+		Short: "Send a saved snapshot back to the board",
+		Args:  exactArgsWithHelp(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSnapshotRestore(cmd, stderr, opts, args[0])
+		},
+	}
+	addForceFlag(restoreCmd, opts)
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a saved snapshot",
+		Args:  exactArgsWithHelp(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSnapshotDelete(cmd, opts, args[0])
+		},
+	}
+
+	exportCmd := &cobra.Command{
+		Use:   "export <name> [file|-]",
+		Short: "Write a snapshot as portable JSON to a file or stdout",
+		Args:  rangeArgsWithHelp(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := "-"
+			if len(args) == 2 {
+				target = args[1]
+			}
+			return runSnapshotExport(cmd, stdout, opts, args[0], target)
+		},
+	}
+
+	var importName string
+	var importForce bool
+	importCmd := &cobra.Command{
+		Use:   "import <file|->",
+		Short: "Import a snapshot exported with snapshot export",
+		Args:  exactArgsWithHelp(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSnapshotImport(cmd, stdin, stdout, opts, args[0], importName, importForce)
+		},
+	}
+	importCmd.Flags().StringVar(&importName, "name", "", "Save under this name instead of the one in the file")
+	importCmd.Flags().BoolVarP(&importForce, "force", "f", false, "Overwrite an existing snapshot")
+
+	snapshotCmd.AddCommand(saveCmd, listCmd, showCmd, restoreCmd, deleteCmd, exportCmd, importCmd)
+	return snapshotCmd
+}
+
+func runSnapshotSave(cmd *cobra.Command, stdout, stderr io.Writer, opts *options, name string, force bool) error {
+	if err := validateSnapshotName(name); err != nil {
+		return usageError(cmd, err)
+	}
+	board, err := buildBoard(stderr, opts)
+	if err != nil {
+		return err
+	}
+	state, err := board.GetCurrentState(cmd.Context())
+	if err != nil {
+		return err
+	}
+	saved := snapshot{
+		Version:   snapshotVersion,
+		Name:      name,
+		SavedAt:   opts.clock.Now().UTC(),
+		MessageID: state.ID,
+		Model:     state.Model,
+		Profile:   boardProfile(opts),
+		Layout:    state.Layout,
+	}
+	if err := writeSnapshot(opts, saved, force); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(stdout, name); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func runSnapshotList(stdout io.Writer, opts *options, jsonOutput bool) error {
+	snapshots, err := listSnapshots(opts)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		out, err := json.Marshal(snapshots)
+		if err != nil {
+			return fmt.Errorf("encode snapshots: %w", err)
+		}
+		if _, err := fmt.Fprintln(stdout, string(out)); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSAVED\tMODEL\tTEXT")
+	for _, saved := range snapshots {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", saved.Name, saved.SavedAt.Local().Format(time.DateTime), saved.Model, summarizeLayout(saved.Layout, saved.Model))
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func runSnapshotShow(cmd *cobra.Command, stdout io.Writer, opts *options, name string) error {
+	saved, err := readSnapshot(cmd, opts, name)
+	if err != nil {
+		return err
+	}
+	if opts.render || opts.text {
+		return writeCharacters(stdout, opts, saved.Layout, saved.Model)
+	}
+	out, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+	if _, err := fmt.Fprintln(stdout, string(out)); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func runSnapshotRestore(cmd *cobra.Command, stderr io.Writer, opts *options, name string) error {
+	saved, err := readSnapshot(cmd, opts, name)
+	if err != nil {
+		return err
+	}
+	board, err := buildBoard(stderr, opts)
+	if err != nil {
+		return err
+	}
+	return deliver(cmd.Context(), stderr, opts, board, delivery{
+		Command:    commandName(cmd),
+		Source:     name,
+		Model:      saved.Model,
+		Characters: saved.Layout,
+	})
+}
+
+func runSnapshotDelete(cmd *cobra.Command, opts *options, name string) error {
+	if _, err := readSnapshot(cmd, opts, name); err != nil {
+		return err
+	}
+	store, err := openState(opts)
+	if err != nil {
+		return err
+	}
+	return store.Remove(snapshotPath(name))
+}
+
+func runSnapshotExport(cmd *cobra.Command, stdout io.Writer, opts *options, name, target string) error {
+	saved, err := readSnapshot(cmd, opts, name)
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+	out = append(out, '\n')
+	if target == "-" {
+		if _, err := stdout.Write(out); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+		return nil
+	}
+	if err := os.WriteFile(target, out, 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", target, err)
+	}
+	return nil
+}
+
+func runSnapshotImport(cmd *cobra.Command, stdin io.Reader, stdout io.Writer, opts *options, source, name string, force bool) error {
+	data, err := readFileArg(stdin, source)
+	if err != nil {
+		return err
+	}
+	var imported snapshot
+	if err := json.Unmarshal(data, &imported); err != nil {
+		return usageError(cmd, fmt.Errorf("snapshot file must be a JSON object: %w", err))
+	}
+	if imported.Version > snapshotVersion {
+		return usageError(cmd, fmt.Errorf("snapshot format version %d is newer than this vbcli supports (%d)", imported.Version, snapshotVersion))
+	}
+	if len(imported.Layout) == 0 {
+		return usageError(cmd, errors.New("snapshot has no layout"))
+	}
+	if name != "" {
+		imported.Name = name
+	}
+	if err := validateSnapshotName(imported.Name); err != nil {
+		return usageError(cmd, err)
+	}
+	imported.Version = snapshotVersion
+	if imported.Model == "" {
+		table, err := codecFor("", imported.Layout)
+		if err != nil {
+			return usageError(cmd, err)
+		}
+		imported.Model = table.Model()
+	}
+	if err := writeSnapshot(opts, imported, force); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(stdout, imported.Name); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func validateSnapshotName(name string) error {
+	if !snapshotName.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name %q (use up to 64 letters, digits, '.', '_' or '-', starting with a letter or digit)", name)
+	}
+	return nil
+}
+
+func snapshotPath(name string) string {
+	return filepath.Join(snapshotDir, name+".json")
+}
+
+func readSnapshot(cmd *cobra.Command, opts *options, name string) (snapshot, error) {
+	if err := validateSnapshotName(name); err != nil {
+		return snapshot{}, usageError(cmd, err)
+	}
+	store, err := openState(opts)
+	if err != nil {
+		return snapshot{}, err
+	}
+	var saved snapshot
+	if err := store.ReadJSON(snapshotPath(name), &saved); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return snapshot{}, fmt.Errorf("snapshot %q not found", name)
+		}
+		return snapshot{}, err
+	}
+	return saved, nil
+}
+
+func writeSnapshot(opts *options, saved snapshot, force bool) error {
+	store, err := openState(opts)
+	if err != nil {
+		return err
+	}
+	if !force {
+		if _, err := os.Stat(store.Path(snapshotPath(saved.Name))); err == nil {
+			return fmt.Errorf("snapshot %q already exists (use --force to overwrite)", saved.Name)
+		}
+	}
+	return store.WriteJSON(snapshotPath(saved.Name), saved)
+}
+
+func listSnapshots(opts *options) ([]snapshot, error) {
+	store, err := openState(opts)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(store.Path(snapshotDir))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("list snapshots: %w", err)
+	}
+	snapshots := []snapshot{}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() || !snapshotName.MatchString(name) {
+			continue
+		}
+		var saved snapshot
+		if err := store.ReadJSON(snapshotPath(name), &saved); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, saved)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Name < snapshots[j].Name })
+	return snapshots, nil
+}
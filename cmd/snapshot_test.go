@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"vbcli/vestaboard"
+)
+
+func TestSnapshotSaveRestoreRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{8, 9}})
+	clock := newInstantClock(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))
+	rootOptions := []Option{WithBoard(fake), WithClock(clock), WithStateDir(t.TempDir())}
+
+	if out, err := runRoot(t, "", rootOptions, "snapshot", "save", "dashboard"); err != nil || out != "dashboard\n" {
+		t.Fatalf("save: %q %v", out, err)
+	}
+	if _, err := runRoot(t, "", rootOptions, "snapshot", "save", "dashboard"); err == nil {
+		t.Fatal("expected error saving over an existing snapshot")
+	}
+	if _, err := runRoot(t, "", rootOptions, "send-raw", "[[1,2]]"); err != nil {
+		t.Fatalf("send-raw: %v", err)
+	}
+	if _, err := runRoot(t, "", rootOptions, "snapshot", "restore", "dashboard"); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if got := fake.Sent[len(fake.Sent)-1]; !reflect.DeepEqual(got, [][]int{{8, 9}}) {
+		t.Fatalf("restored %v", got)
+	}
+
+	out, err := runRoot(t, "", rootOptions, "snapshot", "show", "dashboard")
+	if err != nil {
+		t.Fatalf("show: %v", err)
+	}
+	var shown snapshot
+	if err := json.Unmarshal([]byte(out), &shown); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if shown.Version != 1 || shown.MessageID != "fake-0" || !shown.SavedAt.Equal(clock.Now()) || shown.Profile != "custom" {
+		t.Fatalf("unexpected snapshot: %+v", shown)
+	}
+	if out, err := runRoot(t, "", rootOptions, "snapshot", "show", "--text", "dashboard"); err != nil || out != "HI\n" {
+		t.Fatalf("show --text: %q %v", out, err)
+	}
+}
+
+func TestSnapshotListDeleteExportImport(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{1}})
+	stateDir := t.TempDir()
+	rootOptions := []Option{WithBoard(fake), WithStateDir(stateDir)}
+	for _, name := range []string{"b-second", "a-first"} {
+		if _, err := runRoot(t, "", rootOptions, "snapshot", "save", name); err != nil {
+			t.Fatalf("save %s: %v", name, err)
+		}
+	}
+
+	out, err := runRoot(t, "", rootOptions, "snapshot", "list", "--json")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var listed []snapshot
+	if err := json.Unmarshal([]byte(out), &listed); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if len(listed) != 2 || listed[0].Name != "a-first" || listed[1].Name != "b-second" {
+		t.Fatalf("unexpected list: %+v", listed)
+	}
+	out, err = runRoot(t, "", rootOptions, "snapshot", "list")
+	if err != nil || !strings.HasPrefix(out, "NAME") || !strings.Contains(out, "a-first") {
+		t.Fatalf("list table: %q %v", out, err)
+	}
+
+	exported, err := runRoot(t, "", rootOptions, "snapshot", "export", "a-first")
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	otherOptions := []Option{WithBoard(fake), WithStateDir(t.TempDir())}
+	if out, err := runRoot(t, exported, otherOptions, "snapshot", "import", "--name", "copy", "-"); err != nil || out != "copy\n" {
+		t.Fatalf("import: %q %v", out, err)
+	}
+	if out, err := runRoot(t, "", otherOptions, "snapshot", "show", "--text", "copy"); err != nil || out != "A\n" {
+		t.Fatalf("show imported: %q %v", out, err)
+	}
+
+	if _, err := runRoot(t, "", rootOptions, "snapshot", "delete", "a-first"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := runRoot(t, "", rootOptions, "snapshot", "show", "a-first"); err == nil {
+		t.Fatal("expected error for deleted snapshot")
+	}
+}
+
+func TestSnapshotRejectsBadInput(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{1}})
+	tests := []struct {
+		stdin string
+		args  []string
+	}{
+		{args: []string{"snapshot", "save", "../escape"}},
+		{args: []string{"snapshot", "save", ".hidden"}},
+		{args: []string{"snapshot", "restore", "missing"}},
+		{args: []string{"snapshot", "delete", "missing"}},
+		{stdin: `{"name":"x"}`, args: []string{"snapshot", "import", "-"}},
+		{stdin: `{"version":9,"name":"x","layout":[[1]]}`, args: []string{"snapshot", "import", "-"}},
+		{stdin: `[[1]]`, args: []string{"snapshot", "import", "-"}},
+	}
+	for _, tc := range tests {
+		if _, err := runRoot(t, tc.stdin, []Option{WithBoard(fake)}, tc.args...); err == nil {
+			t.Fatalf("expected error for %v", tc.args)
+		}
+	}
+}
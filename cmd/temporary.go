@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"vbcli/vestaboard"
+)
+
+const (
+	revertDir     = "reverts"
+	revertCommand = "revert"
+)
+
+type temporaryRevert struct {
+	ID            string    `json:"id"`
+	RestoreAt     time.Time `json:"restoreAt"`
+	Previous      [][]int   `json:"previous"`
+	PreviousModel string    `json:"previousModel,omitempty"`
+	Shown         [][]int   `json:"shown"`
+}
+
+func newTemporaryRevert(opts *options, previous *vestaboard.BoardState, shown [][]int) temporaryRevert {
+	now := opts.clock.Now()
+	return temporaryRevert{
+		ID:            strconv.FormatInt(now.UnixNano(), 36),
+		RestoreAt:     now.Add(opts.sendFor).UTC(),
+		Previous:      previous.Layout,
+		PreviousModel: previous.Model,
+		Shown:         shown,
+	}
+}
+
+func newRevertCmd(stderr io.Writer, opts *options) *cobra.Command {
+	revertCmd := &cobra.Command{
+		Use:    revertCommand + " <id>",
+		Short:  "Restore the board after a detached send --for",
+		Hidden: true,
+		Args:   exactArgsWithHelp(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRevert(cmd, stderr, opts, args[0])
+		},
+	}
+	addForceFlag(revertCmd, opts)
+	return revertCmd
+}
+
+func holdTemporary(cmd *cobra.Command, stdout, stderr io.Writer, opts *options, board vestaboard.Board, revert temporaryRevert) error {
+	if !opts.detach {
+		_, _ = fmt.Fprintf(stderr, "restoring the previous message at %s; press Ctrl-C to restore now\n", revert.RestoreAt.Local().Format(time.TimeOnly))
+		return waitAndRestore(cmd, stderr, opts, board, revert)
+	}
+
+	store, err := openState(opts)
+	if err != nil {
+		return err
+	}
+	if err := store.WriteJSON(revertPath(revert.ID), revert); err != nil {
+		return err
+	}
+	args := []string{}
+	// Forward the global flags given on the command line so the background
+	// process talks to the same board.
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if cmd.Root().PersistentFlags().Lookup(flag.Name) != nil {
+			args = append(args, "--"+flag.Name+"="+flag.Value.String())
+		}
+	})
+	if opts.stateDir != "" && !cmd.Flags().Changed("state-dir") {
+		args = append(args, "--state-dir="+opts.stateDir)
+	}
+	if opts.configFile != "" && !cmd.Flags().Changed("config") {
+		args = append(args, "--config="+opts.configFile)
+	}
+	args = append(args, revertCommand, revert.ID)
+	if opts.force {
+		args = append(args, "--force")
+	}
+	if err := opts.startBackground(args); err != nil {
+		_ = store.Remove(revertPath(revert.ID))
+		return fmt.Errorf("start background revert: %w", err)
+	}
+	if _, err := fmt.Fprintln(stdout, revert.ID); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func runRevert(cmd *cobra.Command, stderr io.Writer, opts *options, id string) error {
+	store, err := openState(opts)
+	if err != nil {
+		return err
+	}
+	var revert temporaryRevert
+	if err := store.ReadJSON(revertPath(filepath.Base(id)), &revert); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no pending revert %q", id)
+		}
+		return err
+	}
+	defer func() { _ = store.Remove(revertPath(revert.ID)) }()
+
+	board, err := buildBoard(stderr, opts)
+	if err != nil {
+		return err
+	}
+	return waitAndRestore(cmd, stderr, opts, board, revert)
+}
+
+// waitAndRestore restores at once on SIGINT or SIGTERM; a second signal
+// terminates the process as usual.
+func waitAndRestore(cmd *cobra.Command, stderr io.Writer, opts *options, board vestaboard.Board, revert temporaryRevert) error {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	waitErr := sleepContext(ctx, opts.clock, revert.RestoreAt.Sub(opts.clock.Now()))
+	stop()
+	if waitErr != nil {
+		_, _ = fmt.Fprintln(stderr, "interrupted; restoring the previous message now")
+	}
+
+	return restorePrevious(context.WithoutCancel(cmd.Context()), stderr, opts, board, "send --for", revert)
+}
+
+// restorePrevious leaves the board alone when it no longer shows the
+// temporary message.
+func restorePrevious(ctx context.Context, stderr io.Writer, opts *options, board vestaboard.Board, command string, revert temporaryRevert) error {
+	current, err := board.GetCurrentState(ctx)
+	if err != nil {
+		return err
+	}
+	if len(diffLayouts(current.Layout, revert.Shown)) > 0 {
+		_, _ = fmt.Fprintln(stderr, "the board has changed since the temporary message was sent; leaving it as is")
+		return nil
+	}
+	return deliver(ctx, stderr, opts, board, delivery{
+		Command:    command,
+		Source:     "restore",
+		Model:      revert.PreviousModel,
+		Characters: revert.Previous,
+	})
+}
+
+func revertPath(id string) string {
+	return filepath.Join(revertDir, id+".json")
+}
+
+func startBackground(args []string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	process := exec.Command(executable, args...)
+	detachProcess(process)
+	if err := process.Start(); err != nil {
+		return err
+	}
+	return process.Process.Release()
+}
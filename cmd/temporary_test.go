@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"vbcli/vestaboard"
+)
+
+// hookClock runs hook before each timer fires, to change the world while a
+// command is waiting.
+type hookClock struct {
+	*instantClock
+	hook func()
+}
+
+func (c *hookClock) After(d time.Duration) <-chan time.Time {
+	c.hook()
+	return c.instantClock.After(d)
+}
+
+func TestSendForRestoresPreviousMessage(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{1}})
+	clock := newInstantClock(time.Unix(0, 0))
+	if _, err := runRoot(t, "", []Option{WithBoard(fake), WithClock(clock)}, "send", "--for", "10m", "[[2]]"); err != nil {
+		t.Fatalf("send --for: %v", err)
+	}
+	if want := [][][]int{{{2}}, {{1}}}; !reflect.DeepEqual(fake.Sent, want) {
+		t.Fatalf("sent %v, want %v", fake.Sent, want)
+	}
+	if slept := clock.Slept(); len(slept) != 1 || slept[0] != 10*time.Minute {
+		t.Fatalf("unexpected sleeps: %v", slept)
+	}
+}
+
+func TestSendForLeavesBoardChangedByOthers(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{1}})
+	clock := &hookClock{instantClock: newInstantClock(time.Unix(0, 0))}
+	clock.hook = func() { _ = fake.SendCharacters(context.Background(), [][]int{{9}}) }
+	if _, err := runRoot(t, "", []Option{WithBoard(fake), WithClock(clock)}, "send", "--for", "5m", "[[2]]"); err != nil {
+		t.Fatalf("send --for: %v", err)
+	}
+	if want := [][][]int{{{2}}, {{9}}}; !reflect.DeepEqual(fake.Sent, want) {
+		t.Fatalf("sent %v, want %v", fake.Sent, want)
+	}
+}
+
+func TestSendForDetachHandsOffToRevert(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{1}})
+	clock := newInstantClock(time.Unix(100, 0))
+	stateDir := t.TempDir()
+	configFile := emptyConfig(t)
+	var started []string
+	rootOptions := []Option{WithBoard(fake), WithClock(clock), WithStateDir(stateDir), WithConfigFile(configFile), func(o *options) {
+		o.startBackground = func(args []string) error {
+			started = args
+			return nil
+		}
+	}}
+
+	out, err := runRoot(t, "", rootOptions, "--retries", "2", "send", "--for", "1m", "--detach", "--force", "[[2]]")
+	if err != nil {
+		t.Fatalf("send --for --detach: %v", err)
+	}
+	id := strings.TrimSpace(out)
+	want := []string{"--retries=2", "--state-dir=" + stateDir, "--config=" + configFile, revertCommand, id, "--force"}
+	if !reflect.DeepEqual(started, want) {
+		t.Fatalf("started %v, want %v", started, want)
+	}
+	if len(fake.Sent) != 1 || len(clock.Slept()) != 0 {
+		t.Fatalf("detached send must not wait: sent %v, slept %v", fake.Sent, clock.Slept())
+	}
+
+	if _, err := runRoot(t, "", rootOptions, started...); err != nil {
+		t.Fatalf("revert: %v", err)
+	}
+	if got := fake.Sent[len(fake.Sent)-1]; !reflect.DeepEqual(got, [][]int{{1}}) {
+		t.Fatalf("revert sent %v", got)
+	}
+	if _, err := runRoot(t, "", rootOptions, revertCommand, id); err == nil {
+		t.Fatal("expected the pending revert to be removed")
+	}
+}
+
+func TestSendForRejectsBadFlags(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{1}})
+	for _, args := range [][]string{
+		{"send", "--detach", "[[2]]"},
+		{"send", "--format", "--for", "1m", "[[2]]"},
+		{"send", "--for", "-1m", "[[2]]"},
+	} {
+		if _, err := runRoot(t, "", []Option{WithBoard(fake)}, args...); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+	if len(fake.Sent) != 0 {
+		t.Fatalf("nothing should be sent: %v", fake.Sent)
+	}
+}
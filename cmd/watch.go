@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vbcli/internal/codec"
+	"vbcli/internal/preview"
+	vbapi "vbcli/internal/vestaboard"
+	"vbcli/vestaboard"
+)
+
+const (
+	watchOutputJSONL  = "jsonl"
+	watchOutputRender = "render"
+)
+
+type watchOptions struct {
+	interval   time.Duration
+	maxBackoff time.Duration
+	output     string
+	exec       string
+	count      int
+}
+
+type watchEvent struct {
+	Time      time.Time `json:"time"`
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt,omitzero"`
+	Model     string    `json:"model,omitempty"`
+	Layout    [][]int   `json:"layout"`
+	Text      string    `json:"text"`
+}
+
+func newWatchCmd(stdout, stderr io.Writer, opts *options) *cobra.Command {
+	var watchOpts watchOptions
+	watchCmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Poll the board and report every change of message",
+		Long: `Poll the board and report every change of message.
+
+The first poll reports the current message. Each event is printed as a JSON
+line, redrawn in the terminal (--output render), or piped as JSON to a hook
+command (--exec). Polling slows down while errors repeat and stops cleanly on
+SIGINT or SIGTERM.`,
+		Args: exactArgsWithHelp(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runWatch(cmd, stdout, stderr, opts, watchOpts)
+		},
+	}
+	watchCmd.Flags().DurationVar(&watchOpts.interval, "interval", 30*time.Second, "Time between polls")
+	watchCmd.Flags().DurationVar(&watchOpts.maxBackoff, "max-backoff", 5*time.Minute, "Longest wait between polls while errors repeat")
+	watchCmd.Flags().StringVarP(&watchOpts.output, "output", "o", watchOutputJSONL, "Event output: jsonl or render")
+	watchCmd.Flags().StringVar(&watchOpts.exec, "exec", "", "Run this shell command for each event, with the event JSON on stdin")
+	watchCmd.Flags().IntVar(&watchOpts.count, "count", 0, "Stop after this many events (0 watches until interrupted)")
+	watchCmd.MarkFlagsMutuallyExclusive("output", "exec")
+	return watchCmd
+}
+
+func runWatch(cmd *cobra.Command, stdout, stderr io.Writer, opts *options, watchOpts watchOptions) error {
+	if watchOpts.interval <= 0 {
+		return usageError(cmd, fmt.Errorf("invalid --interval %s (must be positive)", watchOpts.interval))
+	}
+	if watchOpts.count < 0 {
+		return usageError(cmd, fmt.Errorf("invalid --count %d", watchOpts.count))
+	}
+	if watchOpts.output != watchOutputJSONL && watchOpts.output != watchOutputRender {
+		return usageError(cmd, fmt.Errorf("invalid --output %q (expected %q or %q)", watchOpts.output, watchOutputJSONL, watchOutputRender))
+	}
+	board, err := buildBoard(stderr, opts)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var (
+		lastID     string
+		lastLayout [][]int
+		seen       bool
+		failures   int
+		events     int
+	)
+	for {
+		state, err := board.GetCurrentState(ctx)
+		delay := watchOpts.interval
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			var apiErr *vbapi.APIError
+			if errors.As(err, &apiErr) && !apiErr.Retryable() {
+				return err
+			}
+			failures++
+			delay = watchBackoff(watchOpts.interval, watchOpts.maxBackoff, failures)
+			warn(stderr, fmt.Errorf("%w (retrying in %s)", err, delay))
+		default:
+			failures = 0
+			if !seen || state.ID != lastID || len(diffLayouts(state.Layout, lastLayout)) > 0 {
+				seen, lastID, lastLayout = true, state.ID, state.Layout
+				if err := emitWatchEvent(cmd, stdout, stderr, opts, watchOpts, state); err != nil {
+					return err
+				}
+				events++
+				if watchOpts.count > 0 && events >= watchOpts.count {
+					return nil
+				}
+			}
+		}
+		if err := sleepContext(ctx, opts.clock, delay); err != nil {
+			return nil
+		}
+	}
+}
+
+func watchBackoff(interval, limit time.Duration, failures int) time.Duration {
+	delay := interval
+	for i := 1; i < failures && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, max(limit, interval))
+}
+
+func emitWatchEvent(cmd *cobra.Command, stdout, stderr io.Writer, opts *options, watchOpts watchOptions, state *vestaboard.BoardState) error {
+	event := watchEvent{
+		Time:      opts.clock.Now().UTC(),
+		ID:        state.ID,
+		CreatedAt: state.CreatedAt,
+		Model:     state.Model,
+		Layout:    state.Layout,
+		Text:      codec.ForModel(state.Model).Decode(state.Layout),
+	}
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+
+	if watchOpts.exec != "" {
+		if err := runHook(cmd, stdout, stderr, watchOpts.exec, line, state.ID); err != nil {
+			warn(stderr, err)
+		}
+		return nil
+	}
+	if watchOpts.output == watchOutputRender {
+		if preview.IsTerminal(stdout) {
+			// Clear the screen so the board is redrawn in place.
+			if _, err := io.WriteString(stdout, "\x1b[H\x1b[2J"); err != nil {
+				return fmt.Errorf("write output: %w", err)
+			}
+		}
+		if _, err := fmt.Fprintf(stdout, "%s  %s\n", event.Time.Local().Format(time.DateTime), event.ID); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+		return renderPreview(stdout, opts, state.Layout, state.Model, nil)
+	}
+	if _, err := fmt.Fprintln(stdout, string(line)); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func runHook(cmd *cobra.Command, stdout, stderr io.Writer, command string, event []byte, id string) error {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	hook := exec.CommandContext(cmd.Context(), shell, flag, command)
+	hook.Stdin = bytes.NewReader(append(event, '\n'))
+	hook.Stdout = stdout
+	hook.Stderr = stderr
+	hook.Env = append(os.Environ(), "VBCLI_MESSAGE_ID="+id)
+	if err := hook.Run(); err != nil {
+		return fmt.Errorf("hook %q: %w", command, err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	vbapi "vbcli/internal/vestaboard"
+	"vbcli/vestaboard"
+)
+
+func TestWatchEmitsChanges(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{1}})
+	polls := 0
+	clock := &hookClock{instantClock: newInstantClock(time.Unix(0, 0))}
+	clock.hook = func() {
+		polls++
+		// Nothing changes before the second poll.
+		if polls == 2 {
+			_ = fake.SendCharacters(context.Background(), [][]int{{2}})
+		}
+	}
+	out, err := runRoot(t, "", []Option{WithBoard(fake), WithClock(clock)}, "watch", "--interval", "10s", "--count", "2")
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d events:\n%s", len(lines), out)
+	}
+	var first, second watchEvent
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if first.ID != "fake-0" || second.ID != "fake-1" || second.Text != "B" || !reflect.DeepEqual(second.Layout, [][]int{{2}}) {
+		t.Fatalf("unexpected events: %+v %+v", first, second)
+	}
+	if want := []time.Duration{10 * time.Second, 10 * time.Second}; !reflect.DeepEqual(clock.Slept(), want) {
+		t.Fatalf("sleeps %v, want %v", clock.Slept(), want)
+	}
+}
+
+func TestWatchBacksOffOnRepeatedErrors(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{1}})
+	fake.GetErr = &vbapi.APIError{Category: vbapi.CategoryServer, StatusCode: 503}
+	polls := 0
+	clock := &hookClock{instantClock: newInstantClock(time.Unix(0, 0))}
+	clock.hook = func() {
+		polls++
+		if polls == 3 {
+			fake.GetErr = nil
+		}
+	}
+	if _, err := runRoot(t, "", []Option{WithBoard(fake), WithClock(clock)}, "watch", "--interval", "10s", "--max-backoff", "25s", "--count", "1"); err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	want := []time.Duration{10 * time.Second, 20 * time.Second, 25 * time.Second}
+	if !reflect.DeepEqual(clock.Slept(), want) {
+		t.Fatalf("sleeps %v, want %v", clock.Slept(), want)
+	}
+}
+
+func TestWatchStopsOnAuthError(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{1}})
+	fake.GetErr = &vbapi.APIError{Category: vbapi.CategoryAuth, StatusCode: 401}
+	_, err := runRoot(t, "", []Option{WithBoard(fake), WithClock(newInstantClock(time.Unix(0, 0)))}, "watch")
+	var apiErr *vbapi.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected API error, got %v", err)
+	}
+}
+
+func TestWatchExecHook(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("hook uses a POSIX shell")
+	}
+
+	fake := vestaboard.NewFakeBoard([][]int{{8, 9}})
+	out, err := runRoot(t, "", []Option{WithBoard(fake), WithClock(newInstantClock(time.Unix(0, 0)))},
+		"watch", "--count", "1", "--exec", `printf '%s ' "$VBCLI_MESSAGE_ID"; cat`)
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	if !strings.HasPrefix(out, "fake-0 {") || !strings.Contains(out, `"text":"HI"`) {
+		t.Fatalf("unexpected hook output: %q", out)
+	}
+}
+
+func TestWatchRenderAndBadFlags(t *testing.T) {
+	t.Parallel()
+
+	fake := vestaboard.NewFakeBoard([][]int{{1}})
+	rootOptions := []Option{WithBoard(fake), WithClock(newInstantClock(time.Unix(0, 0)))}
+	out, err := runRoot(t, "", rootOptions, "--color", "none", "watch", "-o", "render", "--count", "1")
+	if err != nil || !strings.Contains(out, "fake-0\n+---+\n| A |\n+---+\n") {
+		t.Fatalf("render: %q %v", out, err)
+	}
+	for _, args := range [][]string{
+		{"watch", "--interval", "0s"},
+		{"watch", "-o", "xml"},
+		{"watch", "-o", "render", "--exec", "cat"},
+	} {
+		if _, err := runRoot(t, "", rootOptions, args...); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+}
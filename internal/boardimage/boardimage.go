@@ -0,0 +1,190 @@
+// Package boardimage renders a characters matrix as a PNG, SVG or animated
+// GIF picture of a split-flap board.
+package boardimage
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"vbcli/internal/codec"
+	"vbcli/internal/preview"
+	"vbcli/vestaboard"
+)
+
+// Geometry in pixels at scale 1. Cells keep the tall split-flap proportion
+// of the hardware, so a note (3x15) and a flagship (6x22) get their own
+// aspect ratios from the grid alone.
+const (
+	cellWidth  = 22
+	cellHeight = 34
+	cellGap    = 4
+	margin     = 18
+	dotSize    = 3
+)
+
+var (
+	splitColor = preview.RGB{R: 8, G: 8, B: 8}
+	// unknownColor fills cells whose code has neither a glyph nor a tile.
+	unknownColor = preview.RGB{R: 90, G: 90, B: 90}
+)
+
+// Options controls the rendered image.
+type Options struct {
+	Codec *codec.Codec
+	// Scale multiplies every dimension. Defaults to 1.
+	Scale int
+}
+
+func (o Options) normalized() Options {
+	if o.Codec == nil {
+		o.Codec = codec.Flagship
+	}
+	if o.Scale <= 0 {
+		o.Scale = 1
+	}
+	return o
+}
+
+// Size returns the image size for a board with the given dimensions.
+func Size(rows, columns int, opts Options) (width, height int) {
+	scale := opts.normalized().Scale
+	width = (2*margin + columns*cellWidth + (columns-1)*cellGap) * scale
+	height = (2*margin + rows*cellHeight + (rows-1)*cellGap) * scale
+	return width, height
+}
+
+// Render draws layout into a new RGBA image.
+func Render(layout [][]int, opts Options) *image.RGBA {
+	opts = opts.normalized()
+	rows, columns := vestaboard.Dimensions(layout)
+	width, height := Size(rows, columns, opts)
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	drawBoard(img, layout, rows, columns, opts)
+	return img
+}
+
+// PNG writes layout as a PNG image.
+func PNG(w io.Writer, layout [][]int, opts Options) error {
+	if err := png.Encode(w, Render(layout, opts)); err != nil {
+		return fmt.Errorf("encode png: %w", err)
+	}
+	return nil
+}
+
+// SVG writes layout as an SVG image drawn with the same geometry and glyph
+// font as PNG.
+func SVG(w io.Writer, layout [][]int, opts Options) error {
+	opts = opts.normalized()
+	rows, columns := vestaboard.Dimensions(layout)
+	width, height := Size(rows, columns, opts)
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n", width, height, width, height)
+	fmt.Fprintf(out, `<rect width="%d" height="%d" fill="%s"/>`+"\n", width, height, hex(preview.FrameColor))
+
+	for r := 0; r < rows; r++ {
+		for c := 0; c < columns; c++ {
+			code := codec.Blank
+			if c < len(layout[r]) {
+				code = layout[r][c]
+			}
+			rect := cellRect(r, c, opts.Scale)
+			background, glyph, hasGlyph := cellStyle(code, opts)
+
+			fmt.Fprintf(out, `<g><rect x="%d" y="%d" width="%d" height="%d" rx="%d" fill="%s"/>`,
+				rect.Min.X, rect.Min.Y, rect.Dx(), rect.Dy(), opts.Scale*2, hex(background))
+			if hasGlyph {
+				for _, dot := range glyphDots(rect, glyph, opts.Scale) {
+					fmt.Fprintf(out, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`,
+						dot.Min.X, dot.Min.Y, dot.Dx(), dot.Dy(), hex(preview.GlyphColor))
+				}
+			}
+			split := splitRect(rect, opts.Scale)
+			fmt.Fprintf(out, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/></g>`+"\n",
+				split.Min.X, split.Min.Y, split.Dx(), split.Dy(), hex(splitColor))
+		}
+	}
+	out.WriteString("</svg>\n")
+	if err := out.Flush(); err != nil {
+		return fmt.Errorf("write svg: %w", err)
+	}
+	return nil
+}
+
+func drawBoard(img draw.Image, layout [][]int, rows, columns int, opts Options) {
+	fill(img, img.Bounds(), preview.FrameColor)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < columns; c++ {
+			code := codec.Blank
+			if r < len(layout) && c < len(layout[r]) {
+				code = layout[r][c]
+			}
+			drawCell(img, cellRect(r, c, opts.Scale), code, opts)
+		}
+	}
+}
+
+func drawCell(img draw.Image, rect image.Rectangle, code int, opts Options) {
+	background, glyph, hasGlyph := cellStyle(code, opts)
+	fill(img, rect, background)
+	if hasGlyph {
+		for _, dot := range glyphDots(rect, glyph, opts.Scale) {
+			fill(img, dot, preview.GlyphColor)
+		}
+	}
+	fill(img, splitRect(rect, opts.Scale), splitColor)
+}
+
+func cellStyle(code int, opts Options) (background preview.RGB, glyph rune, hasGlyph bool) {
+	if tile, ok := preview.TileColors[code]; ok {
+		return tile, 0, false
+	}
+	r, ok := opts.Codec.Glyph(code)
+	if !ok {
+		return unknownColor, 0, false
+	}
+	_, inFont := font[r]
+	return preview.CellColor, r, inFont
+}
+
+func cellRect(row, column, scale int) image.Rectangle {
+	x := (margin + column*(cellWidth+cellGap)) * scale
+	y := (margin + row*(cellHeight+cellGap)) * scale
+	return image.Rect(x, y, x+cellWidth*scale, y+cellHeight*scale)
+}
+
+func splitRect(cell image.Rectangle, scale int) image.Rectangle {
+	middle := cell.Min.Y + cell.Dy()/2
+	return image.Rect(cell.Min.X, middle-scale/2, cell.Max.X, middle-scale/2+scale)
+}
+
+func glyphDots(cell image.Rectangle, glyph rune, scale int) []image.Rectangle {
+	bitmap := font[glyph]
+	dot := dotSize * scale
+	left := cell.Min.X + (cell.Dx()-glyphColumns*dot)/2
+	top := cell.Min.Y + (cell.Dy()-glyphRows*dot)/2
+
+	var dots []image.Rectangle
+	for y, row := range bitmap {
+		for x, pixel := range row {
+			if pixel != '#' {
+				continue
+			}
+			dots = append(dots, image.Rect(left+x*dot, top+y*dot, left+(x+1)*dot, top+(y+1)*dot))
+		}
+	}
+	return dots
+}
+
+func fill(img draw.Image, rect image.Rectangle, c preview.RGB) {
+	draw.Draw(img, rect, &image.Uniform{C: color.RGBA{R: c.R, G: c.G, B: c.B, A: 255}}, image.Point{}, draw.Src)
+}
+
+func hex(c preview.RGB) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,124 @@
+package boardimage
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strconv"
+	"strings"
+	"testing"
+
+	"vbcli/internal/codec"
+	"vbcli/internal/preview"
+)
+
+func TestSizeFollowsModelAspect(t *testing.T) {
+	t.Parallel()
+
+	flagshipW, flagshipH := Size(6, 22, Options{})
+	noteW, noteH := Size(3, 15, Options{})
+	if flagshipW <= flagshipH || noteW <= noteH {
+		t.Fatalf("boards should be landscape: flagship %dx%d note %dx%d", flagshipW, flagshipH, noteW, noteH)
+	}
+	if float64(flagshipW)/float64(flagshipH) >= float64(noteW)/float64(noteH) {
+		t.Fatalf("note should be wider relative to its height than flagship")
+	}
+
+	scaledW, scaledH := Size(6, 22, Options{Scale: 3})
+	if scaledW != 3*flagshipW || scaledH != 3*flagshipH {
+		t.Fatalf("scale 3 got %dx%d, want %dx%d", scaledW, scaledH, 3*flagshipW, 3*flagshipH)
+	}
+}
+
+func TestRenderCells(t *testing.T) {
+	t.Parallel()
+
+	img := Render([][]int{{63, 1, 0}}, Options{Scale: 2})
+	red := cellRect(0, 0, 2)
+	if got := img.RGBAAt(red.Min.X+2, red.Min.Y+2); got != rgba(preview.TileColors[codec.Red]) {
+		t.Fatalf("red tile pixel = %v", got)
+	}
+
+	letter := cellRect(0, 1, 2)
+	dots := glyphDots(letter, 'A', 2)
+	if len(dots) == 0 {
+		t.Fatal("expected glyph dots for A")
+	}
+	if got := img.RGBAAt(dots[0].Min.X, dots[0].Min.Y); got != rgba(preview.GlyphColor) {
+		t.Fatalf("glyph pixel = %v", got)
+	}
+
+	blank := cellRect(0, 2, 2)
+	if got := img.RGBAAt(blank.Min.X+2, blank.Min.Y+2); got != rgba(preview.CellColor) {
+		t.Fatalf("blank cell pixel = %v", got)
+	}
+	if got := img.RGBAAt(0, 0); got != rgba(preview.FrameColor) {
+		t.Fatalf("frame pixel = %v", got)
+	}
+}
+
+func TestFontCoversCharacterTables(t *testing.T) {
+	t.Parallel()
+
+	for _, table := range []*codec.Codec{codec.Flagship, codec.Note} {
+		for code := 1; code < codec.Red; code++ {
+			r, ok := table.Glyph(code)
+			if !ok {
+				continue
+			}
+			if _, ok := font[r]; !ok {
+				t.Errorf("%s code %d (%q) has no glyph in the font", table.Model(), code, r)
+			}
+		}
+	}
+	for r, bitmap := range font {
+		for _, row := range bitmap {
+			if len(row) != glyphColumns {
+				t.Errorf("glyph %q has a row of width %d", r, len(row))
+			}
+		}
+	}
+}
+
+func TestPNGRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := PNG(&buf, [][]int{{8, 9}}, Options{}); err != nil {
+		t.Fatalf("PNG: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	width, height := Size(1, 2, Options{})
+	if img.Bounds().Dx() != width || img.Bounds().Dy() != height {
+		t.Fatalf("got %v, want %dx%d", img.Bounds(), width, height)
+	}
+}
+
+func TestSVG(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := SVG(&buf, [][]int{{63, 1}}, Options{Scale: 2}); err != nil {
+		t.Fatalf("SVG: %v", err)
+	}
+	out := buf.String()
+	width, height := Size(1, 2, Options{Scale: 2})
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg"`,
+		`viewBox="0 0 ` + strconv.Itoa(width) + " " + strconv.Itoa(height) + `"`,
+		hex(preview.TileColors[codec.Red]),
+		hex(preview.GlyphColor),
+		"</svg>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("SVG missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func rgba(c preview.RGB) color.RGBA {
+	return color.RGBA{R: c.R, G: c.G, B: c.B, A: 255}
+}
@@ -0,0 +1,66 @@
+package boardimage
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"time"
+
+	"vbcli/internal/codec"
+	"vbcli/internal/flap"
+	"vbcli/internal/preview"
+	"vbcli/vestaboard"
+)
+
+// minGIFDelay is in hundredths of a second; most viewers slow shorter
+// delays down to 100ms.
+const minGIFDelay = 2
+
+// GIF writes frames as an animated GIF that loops forever. Every frame is
+// drawn at the size of the largest one.
+func GIF(w io.Writer, frames []flap.Frame, opts Options) error {
+	if len(frames) == 0 {
+		return errors.New("no frames to encode")
+	}
+	opts = opts.normalized()
+	rows, columns := 0, 0
+	for _, frame := range frames {
+		frameRows, frameColumns := vestaboard.Dimensions(frame.Layout)
+		rows, columns = max(rows, frameRows), max(columns, frameColumns)
+	}
+	width, height := Size(rows, columns, opts)
+	palette := gifPalette()
+
+	animation := &gif.GIF{}
+	for _, frame := range frames {
+		img := image.NewPaletted(image.Rect(0, 0, width, height), palette)
+		drawBoard(img, frame.Layout, rows, columns, opts)
+		animation.Image = append(animation.Image, img)
+		animation.Delay = append(animation.Delay, gifDelay(frame.Duration))
+	}
+	if err := gif.EncodeAll(w, animation); err != nil {
+		return fmt.Errorf("encode gif: %w", err)
+	}
+	return nil
+}
+
+func gifDelay(d time.Duration) int {
+	return max(int((d+5*time.Millisecond)/(10*time.Millisecond)), minGIFDelay)
+}
+
+func gifPalette() color.Palette {
+	colors := []preview.RGB{preview.FrameColor, preview.CellColor, preview.GlyphColor, splitColor, unknownColor}
+	for code := codec.Red; code <= codec.MaxCode; code++ {
+		if tile, ok := preview.TileColors[code]; ok {
+			colors = append(colors, tile)
+		}
+	}
+	palette := make(color.Palette, 0, len(colors))
+	for _, c := range colors {
+		palette = append(palette, color.RGBA{R: c.R, G: c.G, B: c.B, A: 255})
+	}
+	return palette
+}
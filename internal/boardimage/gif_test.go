@@ -0,0 +1,55 @@
+package boardimage
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+	"time"
+
+	"vbcli/internal/flap"
+	"vbcli/internal/preview"
+)
+
+func TestGIFFramesAndDelays(t *testing.T) {
+	t.Parallel()
+
+	frames := []flap.Frame{
+		{Layout: [][]int{{1}}, Duration: 2 * time.Second},
+		{Layout: [][]int{{63, 2}}, Duration: 40 * time.Millisecond},
+		{Layout: [][]int{{3}}, Duration: time.Millisecond},
+	}
+	var buf bytes.Buffer
+	if err := GIF(&buf, frames, Options{}); err != nil {
+		t.Fatalf("GIF: %v", err)
+	}
+	decoded, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(decoded.Image) != 3 {
+		t.Fatalf("got %d frames, want 3", len(decoded.Image))
+	}
+	if want := []int{200, 4, minGIFDelay}; decoded.Delay[0] != want[0] || decoded.Delay[1] != want[1] || decoded.Delay[2] != want[2] {
+		t.Fatalf("delays = %v, want %v", decoded.Delay, want)
+	}
+	width, height := Size(1, 2, Options{})
+	for i, img := range decoded.Image {
+		if img.Bounds().Dx() != width || img.Bounds().Dy() != height {
+			t.Fatalf("frame %d bounds %v, want %dx%d", i, img.Bounds(), width, height)
+		}
+	}
+	red := cellRect(0, 0, 1)
+	r, g, b, _ := decoded.Image[1].At(red.Min.X+2, red.Min.Y+2).RGBA()
+	tile := preview.TileColors[63]
+	if uint8(r>>8) != tile.R || uint8(g>>8) != tile.G || uint8(b>>8) != tile.B {
+		t.Fatalf("red tile pixel = %d,%d,%d", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestGIFRequiresFrames(t *testing.T) {
+	t.Parallel()
+
+	if err := GIF(&bytes.Buffer{}, nil, Options{}); err == nil {
+		t.Fatal("expected error for no frames")
+	}
+}
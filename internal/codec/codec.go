@@ -0,0 +1,270 @@
+// Package codec converts between text and Vestaboard character codes.
+//
+// Codes 1-60 are letters, digits and punctuation and are the same on every
+// model. Code 62 is the degree sign on the flagship and a heart on the note.
+// Codes 63-71 are colour tiles; in text they are written as the same {name}
+// tokens that templates accept, so decoded text can be sent again unchanged.
+package codec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	Blank  = 0
+	Red    = 63
+	Orange = 64
+	Yellow = 65
+	Green  = 66
+	Blue   = 67
+	Violet = 68
+	White  = 69
+	Black  = 70
+	Filled = 71
+
+	MaxCode = 71
+)
+
+// UnsupportedRune reports a character Encode could not map. Line and Column
+// are zero-based and count runes, not bytes. The cell is left blank.
+type UnsupportedRune struct {
+	Rune   rune
+	Line   int
+	Column int
+}
+
+func (u UnsupportedRune) String() string {
+	return fmt.Sprintf("%q at line %d, column %d", u.Rune, u.Line+1, u.Column+1)
+}
+
+// Codec is the character table of one board model.
+type Codec struct {
+	model  string
+	glyphs map[int]rune
+	runes  map[rune]int
+}
+
+var (
+	Flagship = newCodec("flagship", '°')
+	Note     = newCodec("note", '❤')
+)
+
+// ForModel returns the codec for "flagship" or "note"; anything else gets
+// the flagship table.
+func ForModel(model string) *Codec {
+	if strings.EqualFold(strings.TrimSpace(model), Note.model) {
+		return Note
+	}
+	return Flagship
+}
+
+// Encode converts text using the flagship table.
+func Encode(text string) ([][]int, []UnsupportedRune) {
+	return Flagship.Encode(text)
+}
+
+// Decode converts codes to text using the flagship table.
+func Decode(layout [][]int) string {
+	return Flagship.Decode(layout)
+}
+
+var colorNames = map[int]string{
+	Red:    "red",
+	Orange: "orange",
+	Yellow: "yellow",
+	Green:  "green",
+	Blue:   "blue",
+	Violet: "violet",
+	White:  "white",
+	Black:  "black",
+	Filled: "filled",
+}
+
+// names are keyed by their canonical form: lowercase, single spaces.
+var names = map[string]int{
+	"blank":             Blank,
+	"space":             Blank,
+	"exclamation":       37,
+	"exclamation mark":  37,
+	"at":                38,
+	"pound":             39,
+	"hash":              39,
+	"dollar":            40,
+	"left parenthesis":  41,
+	"open parenthesis":  41,
+	"right parenthesis": 42,
+	"close parenthesis": 42,
+	"hyphen":            44,
+	"dash":              44,
+	"plus":              46,
+	"ampersand":         47,
+	"equal":             48,
+	"equals":            48,
+	"semicolon":         49,
+	"colon":             50,
+	"single quote":      52,
+	"apostrophe":        52,
+	"double quote":      53,
+	"percent":           54,
+	"comma":             55,
+	"period":            56,
+	"dot":               56,
+	"slash":             59,
+	"forward slash":     59,
+	"question":          60,
+	"question mark":     60,
+	// Code 62 is one physical flap: a degree sign on the flagship and a
+	// heart on the note. Both names select it on either model.
+	"degree": 62,
+	"heart":  62,
+	"red":    Red,
+	"orange": Orange,
+	"yellow": Yellow,
+	"green":  Green,
+	"blue":   Blue,
+	"violet": Violet,
+	"purple": Violet,
+	"white":  White,
+	"black":  Black,
+	"filled": Filled,
+}
+
+func newCodec(model string, code62 rune) *Codec {
+	glyphs := map[int]rune{
+		Blank: ' ',
+		37:    '!', 38: '@', 39: '#', 40: '$', 41: '(', 42: ')',
+		44: '-', 46: '+', 47: '&', 48: '=', 49: ';', 50: ':',
+		52: '\'', 53: '"', 54: '%', 55: ',', 56: '.', 59: '/',
+		60: '?', 62: code62,
+	}
+	for i := 0; i < 26; i++ {
+		glyphs[i+1] = rune('A' + i)
+	}
+	for i := 0; i < 9; i++ {
+		glyphs[i+27] = rune('1' + i)
+	}
+	glyphs[36] = '0'
+
+	runes := make(map[rune]int, len(glyphs)+32)
+	for code, r := range glyphs {
+		runes[r] = code
+	}
+	for r := 'a'; r <= 'z'; r++ {
+		runes[r] = int(r-'a') + 1
+	}
+	runes['°'] = 62
+	runes['❤'] = 62
+	for code, r := range map[int]rune{Red: '🟥', Orange: '🟧', Yellow: '🟨', Green: '🟩', Blue: '🟦', Violet: '🟪', White: '⬜', Black: '⬛'} {
+		runes[r] = code
+	}
+	return &Codec{model: model, glyphs: glyphs, runes: runes}
+}
+
+// Model returns "flagship" or "note".
+func (c *Codec) Model() string {
+	return c.model
+}
+
+// EncodeRune maps a single character to its code.
+func (c *Codec) EncodeRune(r rune) (int, bool) {
+	code, ok := c.runes[r]
+	return code, ok
+}
+
+// Glyph returns the printable character for a code, or false for colour
+// tiles and unused codes.
+func (c *Codec) Glyph(code int) (rune, bool) {
+	r, ok := c.glyphs[code]
+	return r, ok
+}
+
+// ColorName returns the template name of a colour tile code.
+func ColorName(code int) (string, bool) {
+	name, ok := colorNames[code]
+	return name, ok
+}
+
+// LookupName resolves a template alias such as "green" or "question mark".
+// Case, underscores, hyphens and repeated spaces are ignored.
+func LookupName(name string) (int, bool) {
+	code, ok := names[canonicalName(name)]
+	return code, ok
+}
+
+// Encode converts text to codes, one row per line. {NN} and {name} tokens
+// select a code directly. Rows are not padded or wrapped.
+func (c *Codec) Encode(text string) ([][]int, []UnsupportedRune) {
+	var (
+		layout      [][]int
+		unsupported []UnsupportedRune
+	)
+	for lineIndex, line := range strings.Split(text, "\n") {
+		row := []int{}
+		column := 0
+		for i := 0; i < len(line); {
+			if line[i] == '{' {
+				if end := strings.IndexByte(line[i:], '}'); end > 1 {
+					if code, ok := parseToken(line[i+1 : i+end]); ok {
+						row = append(row, code)
+						column++
+						i += end + 1
+						continue
+					}
+				}
+			}
+
+			r, size := utf8.DecodeRuneInString(line[i:])
+			code, ok := c.runes[r]
+			if !ok {
+				unsupported = append(unsupported, UnsupportedRune{Rune: r, Line: lineIndex, Column: column})
+				code = Blank
+			}
+			row = append(row, code)
+			column++
+			i += size
+		}
+		layout = append(layout, row)
+	}
+	return layout, unsupported
+}
+
+// Decode converts codes to text, one line per row. Colour tiles become
+// {name} tokens and unused codes become {NN}.
+func (c *Codec) Decode(layout [][]int) string {
+	lines := make([]string, len(layout))
+	for i, row := range layout {
+		var line strings.Builder
+		for _, code := range row {
+			line.WriteString(c.DecodeCell(code))
+		}
+		lines[i] = line.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DecodeCell converts one code to its text form.
+func (c *Codec) DecodeCell(code int) string {
+	if r, ok := c.glyphs[code]; ok {
+		return string(r)
+	}
+	if name, ok := colorNames[code]; ok {
+		return "{" + name + "}"
+	}
+	return "{" + strconv.Itoa(code) + "}"
+}
+
+func parseToken(token string) (int, bool) {
+	if code, err := strconv.Atoi(strings.TrimSpace(token)); err == nil {
+		return code, code >= 0 && code <= MaxCode
+	}
+	return LookupName(token)
+}
+
+func canonicalName(input string) string {
+	lower := strings.ToLower(strings.TrimSpace(input))
+	lower = strings.NewReplacer("_", " ", "-", " ").Replace(lower)
+	return strings.Join(strings.Fields(lower), " ")
+}
@@ -0,0 +1,81 @@
+package codec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	got, unsupported := Encode("Hi 10!\n{green}{63} ?")
+	want := [][]int{
+		{8, 9, 0, 27, 36, 37},
+		{Green, Red, 0, 60},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if len(unsupported) != 0 {
+		t.Fatalf("unexpected unsupported runes: %v", unsupported)
+	}
+}
+
+func TestEncodeReportsUnsupportedRunes(t *testing.T) {
+	t.Parallel()
+
+	got, unsupported := Encode("A\nB☃C")
+	if !reflect.DeepEqual(got, [][]int{{1}, {2, 0, 3}}) {
+		t.Fatalf("unexpected layout: %v", got)
+	}
+	if len(unsupported) != 1 || unsupported[0] != (UnsupportedRune{Rune: '☃', Line: 1, Column: 1}) {
+		t.Fatalf("unexpected unsupported runes: %v", unsupported)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	layout := [][]int{{8, 9, 0, 62}, {Red, Filled, 57}}
+	if got := Flagship.Decode(layout); got != "HI °\n{red}{filled}{57}" {
+		t.Fatalf("flagship decode = %q", got)
+	}
+	if got := Note.Decode(layout); got != "HI ❤\n{red}{filled}{57}" {
+		t.Fatalf("note decode = %q", got)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var row []int
+	for code := 0; code <= MaxCode; code++ {
+		row = append(row, code)
+	}
+	for _, table := range []*Codec{Flagship, Note} {
+		text := table.Decode([][]int{row})
+		got, unsupported := table.Encode(text)
+		if len(unsupported) != 0 || !reflect.DeepEqual(got, [][]int{row}) {
+			t.Fatalf("%s round trip failed: %q -> %v (%v)", table.Model(), text, got, unsupported)
+		}
+	}
+}
+
+func TestLookupName(t *testing.T) {
+	t.Parallel()
+
+	if code, ok := LookupName("  Question_Mark "); !ok || code != 60 {
+		t.Fatalf("got %d, %v", code, ok)
+	}
+	if _, ok := LookupName("unknown"); ok {
+		t.Fatal("expected unknown name to be rejected")
+	}
+}
+
+func TestForModel(t *testing.T) {
+	t.Parallel()
+
+	if ForModel("NOTE") != Note || ForModel("flagship") != Flagship || ForModel("") != Flagship {
+		t.Fatal("unexpected codec selection")
+	}
+}
@@ -0,0 +1,68 @@
+// Package config reads vbcli's optional YAML config file.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"vbcli/internal/policy"
+)
+
+// EnvFile overrides the config file path.
+const EnvFile = "VBCLI_CONFIG"
+
+// Config is the contents of the config file.
+type Config struct {
+	// Policy holds the quiet hours that sending commands check.
+	Policy policy.Policy `yaml:"policy"`
+}
+
+// DefaultPath returns $XDG_CONFIG_HOME/vbcli/config.yaml, or
+// ~/.config/vbcli/config.yaml when XDG_CONFIG_HOME is unset.
+func DefaultPath(getenv func(string) string) (string, error) {
+	if dir := getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "vbcli", "config.yaml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("locate config file: %w", err)
+	}
+	return filepath.Join(home, ".config", "vbcli", "config.yaml"), nil
+}
+
+// Load reads the config file at path. A missing file is an empty config
+// unless required is set.
+func Load(path string, required bool) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse parses a config file. Unknown keys are errors.
+func Parse(data []byte) (*Config, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var cfg Config
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	if err := cfg.Policy.Init(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
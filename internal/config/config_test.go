@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing.yaml")
+	cfg, err := Load(missing, false)
+	if err != nil || len(cfg.Policy.Windows) != 0 {
+		t.Fatalf("missing optional config: %+v %v", cfg, err)
+	}
+	if _, err := Load(missing, true); err == nil {
+		t.Fatal("expected error for a missing required config")
+	}
+
+	path := filepath.Join(dir, "config.yaml")
+	content := "policy:\n  windows:\n    - name: night\n      start: '22:00'\n      end: '07:00'\n      action: queue\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = Load(path, true)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(cfg.Policy.Windows) != 1 || cfg.Policy.Windows[0].Action != "queue" {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+
+	if err := os.WriteFile(path, []byte("policy:\n  windows:\n    - action: snooze\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path, true); err == nil || !strings.Contains(err.Error(), path) {
+		t.Fatalf("expected error naming the file, got %v", err)
+	}
+	if _, err := Parse([]byte("polcy: {}\n")); err == nil {
+		t.Fatal("expected error for an unknown key")
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{"XDG_CONFIG_HOME": "/xdg"}
+	path, err := DefaultPath(func(key string) string { return env[key] })
+	if err != nil || path != filepath.Join("/xdg", "vbcli", "config.yaml") {
+		t.Fatalf("got %q %v", path, err)
+	}
+}
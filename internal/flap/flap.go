@@ -0,0 +1,201 @@
+// Package flap simulates how a split-flap board moves from one layout to
+// another. Every cell spins forward through its drum until it reaches the
+// target code; the transition type decides when each cell starts.
+package flap
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"vbcli/internal/codec"
+	"vbcli/vestaboard"
+)
+
+const (
+	TypeClassic = "classic"
+	TypeWave    = "wave"
+	TypeDrift   = "drift"
+	TypeCurtain = "curtain"
+
+	SpeedFast   = "fast"
+	SpeedGentle = "gentle"
+)
+
+// Frame is one layout and how long it stays on screen.
+type Frame struct {
+	Layout   [][]int
+	Duration time.Duration
+}
+
+// Options selects the transition. Seed makes drift reproducible.
+type Options struct {
+	Type  string
+	Speed string
+	Seed  int64
+}
+
+type timing struct {
+	// step is how long one flap stays visible.
+	step time.Duration
+	// stagger is how many steps later the next column or row starts.
+	stagger int
+}
+
+var speeds = map[string]timing{
+	SpeedFast:   {step: 40 * time.Millisecond, stagger: 1},
+	SpeedGentle: {step: 90 * time.Millisecond, stagger: 2},
+}
+
+// Drum lists the codes in the order they pass on a cell's drum: blank,
+// letters, digits, punctuation, then the colour tiles. Every valid code is
+// on it, including those the flagship board has no glyph for, so every
+// cell can reach its target.
+var Drum = buildDrum()
+
+var drumIndex = func() map[int]int {
+	index := make(map[int]int, len(Drum))
+	for i, code := range Drum {
+		index[code] = i
+	}
+	return index
+}()
+
+func buildDrum() []int {
+	drum := make([]int, 0, codec.MaxCode+1)
+	for code := codec.Blank; code <= codec.MaxCode; code++ {
+		drum = append(drum, code)
+	}
+	return drum
+}
+
+// Steps returns how many flaps a cell passes going from one code to another.
+// Invalid codes, which are not on the drum, are treated as blank.
+func Steps(from, to int) int {
+	if from == to {
+		return 0
+	}
+	distance := drumIndex[to] - drumIndex[from]
+	if distance < 0 {
+		distance += len(Drum)
+	}
+	return distance
+}
+
+// Validate reports whether opts names a known type and speed.
+func (o Options) Validate() error {
+	switch o.Type {
+	case TypeClassic, TypeWave, TypeDrift, TypeCurtain:
+	default:
+		return fmt.Errorf("unknown transition type %q (expected %q, %q, %q, or %q)", o.Type, TypeClassic, TypeWave, TypeDrift, TypeCurtain)
+	}
+	if _, ok := speeds[o.Speed]; !ok {
+		return fmt.Errorf("unknown transition speed %q (expected %q or %q)", o.Speed, SpeedFast, SpeedGentle)
+	}
+	return nil
+}
+
+// Transition returns the frames shown while the board moves from one
+// layout to another. The first frame is from, the last is to, and each
+// lasts one flap step. Layouts of different sizes are padded with blanks.
+func Transition(from, to [][]int, opts Options) ([]Frame, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	speed := speeds[opts.Speed]
+	fromRows, fromColumns := vestaboard.Dimensions(from)
+	toRows, toColumns := vestaboard.Dimensions(to)
+	rows, columns := max(fromRows, toRows), max(fromColumns, toColumns)
+	from, to = Pad(from, rows, columns), Pad(to, rows, columns)
+
+	starts := startTicks(rows, columns, speed.stagger, opts)
+	last := 0
+	for r := 0; r < rows; r++ {
+		for c := 0; c < columns; c++ {
+			if Steps(from[r][c], to[r][c]) == 0 {
+				continue
+			}
+			last = max(last, starts[r][c]+Steps(from[r][c], to[r][c]))
+		}
+	}
+
+	frames := make([]Frame, 0, last+1)
+	for tick := 0; tick <= last; tick++ {
+		layout := make([][]int, rows)
+		for r := 0; r < rows; r++ {
+			layout[r] = make([]int, columns)
+			for c := 0; c < columns; c++ {
+				moved := min(max(tick-starts[r][c], 0), Steps(from[r][c], to[r][c]))
+				layout[r][c] = advance(from[r][c], moved)
+			}
+		}
+		frames = append(frames, Frame{Layout: layout, Duration: speed.step})
+	}
+	return frames, nil
+}
+
+// Sequence inserts a transition before every frame after the first. The
+// original frames keep their durations.
+func Sequence(frames []Frame, opts Options) ([]Frame, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	var out []Frame
+	for i, frame := range frames {
+		if i > 0 {
+			between, err := Transition(frames[i-1].Layout, frame.Layout, opts)
+			if err != nil {
+				return nil, err
+			}
+			// The first frame repeats the previous layout and the last one
+			// is the frame itself.
+			if len(between) > 2 {
+				out = append(out, between[1:len(between)-1]...)
+			}
+		}
+		out = append(out, frame)
+	}
+	return out, nil
+}
+
+// Pad returns a copy of layout with exactly rows by columns cells, filling
+// new cells with blanks.
+func Pad(layout [][]int, rows, columns int) [][]int {
+	padded := make([][]int, rows)
+	for r := 0; r < rows; r++ {
+		padded[r] = make([]int, columns)
+		if r < len(layout) {
+			copy(padded[r], layout[r])
+		}
+	}
+	return padded
+}
+
+func advance(code, steps int) int {
+	if steps == 0 {
+		return code
+	}
+	return Drum[(drumIndex[code]+steps)%len(Drum)]
+}
+
+// startTicks returns the step at which each cell begins to move: classic
+// starts every cell together, wave sweeps diagonally from the top left,
+// curtain drops row by row and drift starts cells at random.
+func startTicks(rows, columns, stagger int, opts Options) [][]int {
+	random := rand.New(rand.NewSource(opts.Seed))
+	starts := make([][]int, rows)
+	for r := 0; r < rows; r++ {
+		starts[r] = make([]int, columns)
+		for c := 0; c < columns; c++ {
+			switch opts.Type {
+			case TypeWave:
+				starts[r][c] = (r + c) * stagger
+			case TypeCurtain:
+				starts[r][c] = r * 4 * stagger
+			case TypeDrift:
+				starts[r][c] = random.Intn(columns*stagger + 1)
+			}
+		}
+	}
+	return starts
+}
@@ -0,0 +1,141 @@
+package flap
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"vbcli/internal/codec"
+)
+
+func TestSteps(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		from, to int
+		want     int
+	}{
+		{name: "same", from: 5, to: 5, want: 0},
+		{name: "blank to A", from: codec.Blank, to: 1, want: 1},
+		{name: "A to C", from: 1, to: 3, want: 2},
+		{name: "wraps around", from: codec.MaxCode, to: codec.Blank, want: 1},
+		{name: "C to A wraps", from: 3, to: 1, want: len(Drum) - 2},
+		{name: "to a code without a flagship glyph", from: 42, to: 43, want: 1},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := Steps(tc.from, tc.to); got != tc.want {
+				t.Fatalf("Steps(%d, %d) = %d, want %d", tc.from, tc.to, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTransitionClassic(t *testing.T) {
+	t.Parallel()
+
+	frames, err := Transition([][]int{{0, 1}}, [][]int{{2, 1}}, Options{Type: TypeClassic, Speed: SpeedFast})
+	if err != nil {
+		t.Fatalf("Transition: %v", err)
+	}
+	want := [][][]int{{{0, 1}}, {{1, 1}}, {{2, 1}}}
+	if len(frames) != len(want) {
+		t.Fatalf("got %d frames, want %d", len(frames), len(want))
+	}
+	for i, frame := range frames {
+		if !reflect.DeepEqual(frame.Layout, want[i]) {
+			t.Fatalf("frame %d = %v, want %v", i, frame.Layout, want[i])
+		}
+		if frame.Duration != 40*time.Millisecond {
+			t.Fatalf("frame %d duration = %s", i, frame.Duration)
+		}
+	}
+}
+
+func TestTransitionWaveStaggersColumns(t *testing.T) {
+	t.Parallel()
+
+	frames, err := Transition([][]int{{0, 0, 0}}, [][]int{{1, 1, 1}}, Options{Type: TypeWave, Speed: SpeedGentle})
+	if err != nil {
+		t.Fatalf("Transition: %v", err)
+	}
+	// Gentle staggers by two steps per column: the last cell starts at step 4.
+	if len(frames) != 6 {
+		t.Fatalf("got %d frames, want 6", len(frames))
+	}
+	if !reflect.DeepEqual(frames[2].Layout, [][]int{{1, 0, 0}}) {
+		t.Fatalf("frame 2 = %v", frames[2].Layout)
+	}
+	if !reflect.DeepEqual(frames[5].Layout, [][]int{{1, 1, 1}}) {
+		t.Fatalf("last frame = %v", frames[5].Layout)
+	}
+}
+
+func TestTransitionEndsOnTarget(t *testing.T) {
+	t.Parallel()
+
+	// 43, 45, 57 and 61 have no flagship glyph but are valid codes.
+	from := [][]int{{8, 5, 12}, {63, 0, 70}, {57, 0, 0}}
+	to := [][]int{{1, 0, 71}, {0, 36, 63}, {0, 61, 43}}
+	for _, kind := range []string{TypeClassic, TypeWave, TypeDrift, TypeCurtain} {
+		frames, err := Transition(from, to, Options{Type: kind, Speed: SpeedFast, Seed: 7})
+		if err != nil {
+			t.Fatalf("%s: %v", kind, err)
+		}
+		if !reflect.DeepEqual(frames[0].Layout, from) {
+			t.Fatalf("%s: first frame = %v", kind, frames[0].Layout)
+		}
+		if !reflect.DeepEqual(frames[len(frames)-1].Layout, to) {
+			t.Fatalf("%s: last frame = %v", kind, frames[len(frames)-1].Layout)
+		}
+	}
+}
+
+func TestTransitionPadsDifferentSizes(t *testing.T) {
+	t.Parallel()
+
+	frames, err := Transition([][]int{{1}}, [][]int{{1, 0}, {0, 0}}, Options{Type: TypeClassic, Speed: SpeedFast})
+	if err != nil {
+		t.Fatalf("Transition: %v", err)
+	}
+	if len(frames) != 1 || !reflect.DeepEqual(frames[0].Layout, [][]int{{1, 0}, {0, 0}}) {
+		t.Fatalf("unexpected frames: %v", frames)
+	}
+}
+
+func TestSequenceKeepsFrameDurations(t *testing.T) {
+	t.Parallel()
+
+	input := []Frame{
+		{Layout: [][]int{{0}}, Duration: time.Second},
+		{Layout: [][]int{{3}}, Duration: 2 * time.Second},
+		{Layout: [][]int{{3}}, Duration: 3 * time.Second},
+	}
+	frames, err := Sequence(input, Options{Type: TypeClassic, Speed: SpeedFast})
+	if err != nil {
+		t.Fatalf("Sequence: %v", err)
+	}
+	var durations []time.Duration
+	for _, frame := range frames {
+		durations = append(durations, frame.Duration)
+	}
+	step := 40 * time.Millisecond
+	want := []time.Duration{time.Second, step, step, 2 * time.Second, 3 * time.Second}
+	if !reflect.DeepEqual(durations, want) {
+		t.Fatalf("durations = %v, want %v", durations, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	if err := (Options{Type: "spin", Speed: SpeedFast}).Validate(); err == nil {
+		t.Fatal("expected error for unknown type")
+	}
+	if err := (Options{Type: TypeWave, Speed: "slow"}).Validate(); err == nil {
+		t.Fatal("expected error for unknown speed")
+	}
+}
@@ -0,0 +1,181 @@
+// Package playlist reads playlist files, which list messages to rotate
+// through on the board, and works out the order they are shown in.
+package playlist
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultDuration is how long an item stays up when neither the item nor
+// the playlist sets a duration.
+const DefaultDuration = time.Minute
+
+// Playlist is a parsed playlist file. Its model, align, justify and
+// duration are defaults for items that do not set their own.
+type Playlist struct {
+	Model    string        `yaml:"model"`
+	Align    string        `yaml:"align"`
+	Justify  string        `yaml:"justify"`
+	Duration time.Duration `yaml:"duration"`
+	Shuffle  bool          `yaml:"shuffle"`
+	Items    []*Item       `yaml:"items"`
+}
+
+// Item is one message in the rotation. Exactly one of Message, Characters
+// and File is set; a file holds template text or a characters JSON array.
+type Item struct {
+	Name       string        `yaml:"name"`
+	Message    string        `yaml:"message"`
+	Characters [][]int       `yaml:"characters"`
+	File       string        `yaml:"file"`
+	Model      string        `yaml:"model"`
+	Align      string        `yaml:"align"`
+	Justify    string        `yaml:"justify"`
+	Duration   time.Duration `yaml:"duration"`
+	Transition *Transition   `yaml:"transition"`
+	// Weight is how many times the item comes up in each cycle. It
+	// defaults to 1.
+	Weight int `yaml:"weight"`
+}
+
+// Transition is set on the board before the item is sent.
+type Transition struct {
+	Type  string `yaml:"type"`
+	Speed string `yaml:"speed"`
+}
+
+// Load reads and parses a playlist file. Item files are read relative to
+// the playlist's directory.
+func Load(path string) (*Playlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read playlist: %w", err)
+	}
+	playlist, err := Parse(data, filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return playlist, nil
+}
+
+// Parse parses a playlist from YAML, reading item files relative to dir.
+// Unknown keys are errors.
+func Parse(data []byte, dir string) (*Playlist, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var playlist Playlist
+	if err := decoder.Decode(&playlist); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse playlist: %w", err)
+	}
+	if err := playlist.init(dir); err != nil {
+		return nil, err
+	}
+	return &playlist, nil
+}
+
+func (p *Playlist) init(dir string) error {
+	if len(p.Items) == 0 {
+		return errors.New("playlist has no items")
+	}
+	if p.Duration < 0 {
+		return fmt.Errorf("invalid duration %s", p.Duration)
+	}
+	if p.Duration == 0 {
+		p.Duration = DefaultDuration
+	}
+	names := make(map[string]bool, len(p.Items))
+	for i, item := range p.Items {
+		if item == nil {
+			return fmt.Errorf("item %d is empty", i+1)
+		}
+		if item.Name == "" {
+			item.Name = fmt.Sprintf("item-%d", i+1)
+		}
+		if names[item.Name] {
+			return fmt.Errorf("duplicate item name %q", item.Name)
+		}
+		names[item.Name] = true
+
+		set := 0
+		for _, present := range []bool{item.Message != "", len(item.Characters) > 0, item.File != ""} {
+			if present {
+				set++
+			}
+		}
+		if set != 1 {
+			return fmt.Errorf("item %q: set exactly one of message, characters and file", item.Name)
+		}
+		if item.File != "" {
+			path := item.File
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("item %q: %w", item.Name, err)
+			}
+			item.Message = strings.TrimSpace(string(data))
+			if item.Message == "" {
+				return fmt.Errorf("item %q: %s is empty", item.Name, item.File)
+			}
+		}
+
+		if item.Model == "" {
+			item.Model = p.Model
+		}
+		if item.Align == "" {
+			item.Align = p.Align
+		}
+		if item.Justify == "" {
+			item.Justify = p.Justify
+		}
+		if item.Duration < 0 {
+			return fmt.Errorf("item %q: invalid duration %s", item.Name, item.Duration)
+		}
+		if item.Duration == 0 {
+			item.Duration = p.Duration
+		}
+		if item.Weight < 0 {
+			return fmt.Errorf("item %q: invalid weight %d", item.Name, item.Weight)
+		}
+		if item.Weight == 0 {
+			item.Weight = 1
+		}
+	}
+	return nil
+}
+
+// Order returns the item indexes for one cycle, with each item appearing
+// Weight times. Without rng, repeats are spread out round-robin; with rng,
+// the cycle is shuffled.
+func (p *Playlist) Order(rng *rand.Rand) []int {
+	var order []int
+	for round := 0; ; round++ {
+		added := false
+		for i, item := range p.Items {
+			if item.Weight > round {
+				order = append(order, i)
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	if rng != nil {
+		rng.Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+	}
+	return order
+}
@@ -0,0 +1,88 @@
+package playlist
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestLoadAppliesDefaultsAndReadsFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "menu.txt"), []byte("Soup of the day\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "playlist.yaml")
+	content := `
+model: note
+duration: 30s
+items:
+  - message: "Welcome"
+    weight: 2
+  - name: menu
+    file: menu.txt
+    justify: left
+    duration: 2m
+    transition: {type: wave, speed: fast}
+  - characters: [[1, 2]]
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	playlist, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	first, menu, raw := playlist.Items[0], playlist.Items[1], playlist.Items[2]
+	if first.Name != "item-1" || first.Model != "note" || first.Duration != 30*time.Second || first.Weight != 2 {
+		t.Fatalf("unexpected defaults: %+v", first)
+	}
+	if menu.Message != "Soup of the day" || menu.Justify != "left" || menu.Duration != 2*time.Minute || menu.Transition.Type != "wave" {
+		t.Fatalf("unexpected file item: %+v", menu)
+	}
+	if raw.Weight != 1 || raw.Characters[0][1] != 2 {
+		t.Fatalf("unexpected raw item: %+v", raw)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tests := map[string]string{
+		"empty":        ``,
+		"unknown key":  "items:\n  - mesage: hi\n",
+		"nothing":      "items:\n  - name: a\n",
+		"two sources":  "items:\n  - message: hi\n    characters: [[1]]\n",
+		"missing file": "items:\n  - file: nope.txt\n",
+		"duplicate":    "items:\n  - name: a\n    message: hi\n  - name: a\n    message: ho\n",
+		"bad duration": "items:\n  - message: hi\n    duration: soon\n",
+		"negative":     "items:\n  - message: hi\n    weight: -1\n",
+	}
+	for name, input := range tests {
+		if _, err := Parse([]byte(input), dir); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestOrder(t *testing.T) {
+	t.Parallel()
+
+	playlist := &Playlist{Items: []*Item{{Weight: 3}, {Weight: 1}, {Weight: 2}}}
+	if got, want := playlist.Order(nil), []int{0, 1, 2, 0, 2, 0}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("order %v, want %v", got, want)
+	}
+
+	shuffled := playlist.Order(rand.New(rand.NewSource(1)))
+	sorted := append([]int(nil), shuffled...)
+	sort.Ints(sorted)
+	if want := []int{0, 0, 0, 1, 2, 2}; !reflect.DeepEqual(sorted, want) {
+		t.Fatalf("shuffled order %v does not keep the weights", shuffled)
+	}
+}
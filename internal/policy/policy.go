@@ -0,0 +1,228 @@
+// Package policy decides whether a message may be sent at a given time.
+// A policy lists quiet-hours windows, each with an action for sends that
+// fall inside it.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ActionBlock rejects the send.
+	ActionBlock = "block"
+	// ActionQueue holds the send until the window ends.
+	ActionQueue = "queue"
+	// ActionBlank sends a blank layout, or the window's replacement
+	// characters, instead of the message.
+	ActionBlank = "blank"
+)
+
+var dayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Policy is the send policy from the config file. No windows means every
+// send is allowed.
+type Policy struct {
+	Windows []*Window `yaml:"windows"`
+}
+
+// Window is a recurring period of quiet hours. Start and End are "15:04"
+// times in the window's time zone; a window whose end is before its start
+// runs past midnight into the next day. Without Start and End it covers
+// whole days. Days lists the days the window starts on, such as "mon-fri"
+// or "sat"; empty means every day.
+type Window struct {
+	Name       string   `yaml:"name"`
+	Days       []string `yaml:"days"`
+	Start      string   `yaml:"start"`
+	End        string   `yaml:"end"`
+	TimeZone   string   `yaml:"timezone"`
+	Action     string   `yaml:"action"`
+	Characters [][]int  `yaml:"characters"`
+
+	days       [7]bool
+	start, end int
+	location   *time.Location
+}
+
+// Decision is the outcome of checking a send. Window is nil when the send
+// is allowed; Until is when the window ends, or the zero time if it never
+// does.
+type Decision struct {
+	Window *Window
+	Until  time.Time
+}
+
+// Allowed reports whether the send may go ahead unchanged.
+func (d Decision) Allowed() bool {
+	return d.Window == nil
+}
+
+// Init validates the policy and prepares its windows for Check.
+func (p *Policy) Init() error {
+	for i, window := range p.Windows {
+		if window == nil {
+			return fmt.Errorf("quiet hours window %d is empty", i+1)
+		}
+		if window.Name == "" {
+			window.Name = fmt.Sprintf("window-%d", i+1)
+		}
+		if err := window.init(); err != nil {
+			return fmt.Errorf("quiet hours window %q: %w", window.Name, err)
+		}
+	}
+	return nil
+}
+
+func (w *Window) init() error {
+	switch w.Action {
+	case ActionBlock, ActionQueue, ActionBlank:
+	case "":
+		w.Action = ActionBlock
+	default:
+		return fmt.Errorf("invalid action %q (expected %q, %q or %q)", w.Action, ActionBlock, ActionQueue, ActionBlank)
+	}
+	if len(w.Characters) > 0 && w.Action != ActionBlank {
+		return fmt.Errorf("characters only apply to the %q action", ActionBlank)
+	}
+
+	w.location = time.Local
+	if w.TimeZone != "" {
+		location, err := time.LoadLocation(w.TimeZone)
+		if err != nil {
+			return fmt.Errorf("invalid time zone %q: %w", w.TimeZone, err)
+		}
+		w.location = location
+	}
+
+	if len(w.Days) == 0 {
+		w.days = [7]bool{true, true, true, true, true, true, true}
+	}
+	for _, spec := range w.Days {
+		if err := w.addDays(spec); err != nil {
+			return err
+		}
+	}
+
+	if (w.Start == "") != (w.End == "") {
+		return errors.New("set both start and end, or neither for whole days")
+	}
+	if w.Start == "" {
+		return nil
+	}
+	var err error
+	if w.start, err = parseClock(w.Start); err != nil {
+		return err
+	}
+	if w.end, err = parseClock(w.End); err != nil {
+		return err
+	}
+	if w.start == w.end {
+		return errors.New("start and end must differ")
+	}
+	return nil
+}
+
+func (w *Window) addDays(spec string) error {
+	first, last, isRange := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), "-")
+	from := dayIndex(first)
+	to := from
+	if isRange {
+		to = dayIndex(last)
+	}
+	if from < 0 || to < 0 {
+		return fmt.Errorf("invalid day %q (expected a name such as mon, or a range such as mon-fri)", spec)
+	}
+	// Ranges may wrap around the week, as in fri-mon.
+	for d := from; ; d = (d + 1) % 7 {
+		w.days[d] = true
+		if d == to {
+			return nil
+		}
+	}
+}
+
+func dayIndex(name string) int {
+	for i, day := range dayNames {
+		if name == day {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseClock accepts "24:00" as the end of the day.
+func parseClock(value string) (int, error) {
+	hours, minutes, ok := strings.Cut(value, ":")
+	h, errH := strconv.Atoi(hours)
+	m, errM := strconv.Atoi(minutes)
+	if !ok || errH != nil || errM != nil || h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", value)
+	}
+	return h*60 + m, nil
+}
+
+// Check returns the decision for a send at t. When windows overlap, the
+// first one listed applies.
+func (p *Policy) Check(t time.Time) Decision {
+	for _, window := range p.Windows {
+		if until, ok := window.active(t); ok {
+			return Decision{Window: window, Until: until}
+		}
+	}
+	return Decision{}
+}
+
+func (w *Window) active(t time.Time) (time.Time, bool) {
+	local := t.In(w.location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, w.location)
+	weekday := int(local.Weekday())
+	minute := local.Hour()*60 + local.Minute()
+
+	switch {
+	case w.Start == "":
+		if !w.days[weekday] {
+			return time.Time{}, false
+		}
+		// Whole-day windows on consecutive days run together.
+		end := midnight.AddDate(0, 0, 1)
+		for i := 0; w.days[int(end.Weekday())]; i++ {
+			if i == 6 {
+				return time.Time{}, true
+			}
+			end = end.AddDate(0, 0, 1)
+		}
+		return end, true
+	case w.start < w.end:
+		if w.days[weekday] && minute >= w.start && minute < w.end {
+			return clockOn(midnight, w.end), true
+		}
+	default:
+		if w.days[weekday] && minute >= w.start {
+			return clockOn(midnight.AddDate(0, 0, 1), w.end), true
+		}
+		if w.days[(weekday+6)%7] && minute < w.end {
+			return clockOn(midnight, w.end), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func clockOn(midnight time.Time, minutes int) time.Time {
+	return time.Date(midnight.Year(), midnight.Month(), midnight.Day(), 0, minutes, 0, 0, midnight.Location())
+}
+
+// Replacement returns what a blank window sends instead of characters.
+func (w *Window) Replacement(characters [][]int) [][]int {
+	if len(w.Characters) > 0 {
+		return w.Characters
+	}
+	blank := make([][]int, len(characters))
+	for i, row := range characters {
+		blank[i] = make([]int, len(row))
+	}
+	return blank
+}
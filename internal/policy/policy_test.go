@@ -0,0 +1,105 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	policy := &Policy{Windows: []*Window{
+		{Name: "night", Start: "22:00", End: "07:00", TimeZone: "Europe/London", Action: ActionQueue},
+		{Name: "weekend", Days: []string{"sat-sun"}, TimeZone: "Europe/London"},
+		{Name: "standup", Days: []string{"mon-fri"}, Start: "09:00", End: "09:15", TimeZone: "Europe/London", Action: ActionBlank},
+	}}
+	if err := policy.Init(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+
+	at := func(day, hour, minute int) time.Time {
+		// October 2026: the 16th is a Friday.
+		return time.Date(2026, 10, day, hour, minute, 0, 0, london)
+	}
+	tests := []struct {
+		name   string
+		at     time.Time
+		window string
+		until  time.Time
+	}{
+		{name: "weekday afternoon", at: at(16, 15, 0)},
+		{name: "late evening", at: at(15, 23, 30), window: "night", until: at(16, 7, 0)},
+		{name: "early morning", at: at(16, 6, 59), window: "night", until: at(16, 7, 0)},
+		{name: "end is exclusive", at: at(16, 7, 0)},
+		{name: "weekend runs until monday", at: at(17, 12, 0), window: "weekend", until: at(19, 0, 0)},
+		{name: "first window wins", at: at(17, 23, 0), window: "night", until: at(18, 7, 0)},
+		{name: "standup", at: at(16, 9, 10), window: "standup", until: at(16, 9, 15)},
+		{name: "other time zone", at: time.Date(2026, 10, 16, 21, 30, 0, 0, time.UTC), window: "night", until: at(17, 7, 0)},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			decision := policy.Check(tc.at)
+			if tc.window == "" {
+				if !decision.Allowed() {
+					t.Fatalf("expected allowed, got window %q", decision.Window.Name)
+				}
+				return
+			}
+			if decision.Allowed() || decision.Window.Name != tc.window || !decision.Until.Equal(tc.until) {
+				t.Fatalf("got %+v, want %s until %s", decision, tc.window, tc.until)
+			}
+		})
+	}
+}
+
+func TestCheckEveryDayNeverEnds(t *testing.T) {
+	t.Parallel()
+
+	policy := &Policy{Windows: []*Window{{Name: "always"}}}
+	if err := policy.Init(); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	decision := policy.Check(time.Now())
+	if decision.Allowed() || !decision.Until.IsZero() {
+		t.Fatalf("unexpected decision: %+v", decision)
+	}
+}
+
+func TestInitErrors(t *testing.T) {
+	t.Parallel()
+
+	for name, window := range map[string]*Window{
+		"action":     {Action: "dim"},
+		"day":        {Days: []string{"someday"}},
+		"half open":  {Start: "22:00"},
+		"bad time":   {Start: "25:00", End: "07:00"},
+		"same":       {Start: "07:00", End: "07:00"},
+		"zone":       {TimeZone: "Mars/Olympus"},
+		"characters": {Action: ActionBlock, Characters: [][]int{{1}}},
+	} {
+		policy := &Policy{Windows: []*Window{window}}
+		if err := policy.Init(); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestReplacement(t *testing.T) {
+	t.Parallel()
+
+	window := &Window{}
+	if got := window.Replacement([][]int{{1, 2}, {3, 4}}); !reflect.DeepEqual(got, [][]int{{0, 0}, {0, 0}}) {
+		t.Fatalf("unexpected blank layout: %v", got)
+	}
+	window.Characters = [][]int{{70}}
+	if got := window.Replacement([][]int{{1}}); !reflect.DeepEqual(got, [][]int{{70}}) {
+		t.Fatalf("unexpected replacement: %v", got)
+	}
+}
@@ -0,0 +1,308 @@
+// Package preview draws a characters matrix in a terminal, using ANSI
+// colours where the terminal supports them and plain ASCII otherwise.
+package preview
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"vbcli/internal/codec"
+	"vbcli/vestaboard"
+)
+
+// Mode is the colour capability used to draw a board.
+type Mode int
+
+const (
+	ModeASCII Mode = iota
+	Mode16
+	Mode256
+	ModeTrueColor
+)
+
+// RGB is a 24-bit colour.
+type RGB struct {
+	R, G, B uint8
+}
+
+// Palette colours, shared with the image renderers so every preview of a
+// board looks the same.
+var (
+	FrameColor = RGB{12, 12, 12}
+	CellColor  = RGB{34, 34, 34}
+	GlyphColor = RGB{240, 240, 240}
+
+	TileColors = map[int]RGB{
+		codec.Red:    {218, 41, 28},
+		codec.Orange: {255, 117, 0},
+		codec.Yellow: {255, 184, 0},
+		codec.Green:  {0, 154, 68},
+		codec.Blue:   {0, 112, 192},
+		codec.Violet: {112, 48, 160},
+		codec.White:  {255, 255, 255},
+		codec.Black:  {0, 0, 0},
+		codec.Filled: {255, 255, 255},
+	}
+)
+
+// asciiTiles are lowercase, which board glyphs never are.
+var asciiTiles = map[int]rune{
+	codec.Red:    'r',
+	codec.Orange: 'o',
+	codec.Yellow: 'y',
+	codec.Green:  'g',
+	codec.Blue:   'b',
+	codec.Violet: 'v',
+	codec.White:  'w',
+	codec.Black:  'k',
+	codec.Filled: '#',
+}
+
+// Options controls Render.
+type Options struct {
+	Mode  Mode
+	Codec *codec.Codec
+	// Highlight marks cells to emphasise, e.g. cells that differ in a diff.
+	Highlight func(row, column int) bool
+}
+
+// ParseMode accepts "auto", "truecolor", "256", "16" and "none"/"ascii".
+// "auto" detects the capability of out.
+func ParseMode(value string, out io.Writer) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "auto":
+		return DetectMode(out, os.Getenv), nil
+	case "truecolor", "24bit":
+		return ModeTrueColor, nil
+	case "256":
+		return Mode256, nil
+	case "16":
+		return Mode16, nil
+	case "none", "ascii":
+		return ModeASCII, nil
+	default:
+		return ModeASCII, fmt.Errorf("invalid --color %q (expected \"auto\", \"truecolor\", \"256\", \"16\", or \"none\")", value)
+	}
+}
+
+// DetectMode picks the richest mode out supports, honouring NO_COLOR.
+func DetectMode(out io.Writer, getenv func(string) string) Mode {
+	if getenv("NO_COLOR") != "" || !IsTerminal(out) {
+		return ModeASCII
+	}
+	term := strings.ToLower(getenv("TERM"))
+	if term == "dumb" {
+		return ModeASCII
+	}
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return ModeTrueColor
+	}
+	if strings.Contains(term, "256color") {
+		return Mode256
+	}
+	return Mode16
+}
+
+// IsTerminal reports whether w is a character device such as a TTY.
+func IsTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return (info.Mode() & os.ModeCharDevice) != 0
+}
+
+// Render draws layout to w.
+func Render(w io.Writer, layout [][]int, opts Options) error {
+	if opts.Codec == nil {
+		opts.Codec = codec.Flagship
+	}
+	out := bufio.NewWriter(w)
+	if opts.Mode == ModeASCII {
+		renderASCII(out, layout, opts)
+	} else {
+		renderColor(out, layout, opts)
+	}
+	return out.Flush()
+}
+
+func renderASCII(out *bufio.Writer, layout [][]int, opts Options) {
+	_, columns := vestaboard.Dimensions(layout)
+	border := "+" + strings.Repeat("-", columns*3) + "+\n"
+	out.WriteString(border)
+	for r, row := range layout {
+		out.WriteByte('|')
+		for c := 0; c < columns; c++ {
+			code := codec.Blank
+			if c < len(row) {
+				code = row[c]
+			}
+			left, right := ' ', ' '
+			if opts.Highlight != nil && opts.Highlight(r, c) {
+				left, right = '[', ']'
+			}
+			out.WriteRune(left)
+			out.WriteRune(asciiGlyph(opts.Codec, code))
+			out.WriteRune(right)
+		}
+		out.WriteString("|\n")
+	}
+	out.WriteString(border)
+}
+
+func renderColor(out *bufio.Writer, layout [][]int, opts Options) {
+	_, columns := vestaboard.Dimensions(layout)
+	frame := background(opts.Mode, FrameColor)
+	width := columns*4 + 1
+
+	out.WriteString(frame + strings.Repeat(" ", width) + reset + "\n")
+	for r, row := range layout {
+		out.WriteString(frame + " ")
+		for c := 0; c < columns; c++ {
+			code := codec.Blank
+			if c < len(row) {
+				code = row[c]
+			}
+			highlighted := opts.Highlight != nil && opts.Highlight(r, c)
+			out.WriteString(cell(opts, code, highlighted))
+			out.WriteString(frame + " ")
+		}
+		out.WriteString(reset + "\n")
+	}
+	out.WriteString(frame + strings.Repeat(" ", width) + reset + "\n")
+}
+
+const (
+	reset     = "\x1b[0m"
+	bold      = "\x1b[1m"
+	underline = "\x1b[4m"
+)
+
+func cell(opts Options, code int, highlighted bool) string {
+	var b strings.Builder
+	if tile, ok := TileColors[code]; ok {
+		b.WriteString(background(opts.Mode, tile))
+		if highlighted {
+			b.WriteString(foreground(opts.Mode, contrast(tile)) + bold + "[ ]")
+		} else {
+			b.WriteString("   ")
+		}
+		b.WriteString(reset)
+		return b.String()
+	}
+
+	glyph := '?'
+	if r, ok := opts.Codec.Glyph(code); ok {
+		glyph = r
+	}
+	b.WriteString(background(opts.Mode, CellColor))
+	b.WriteString(foreground(opts.Mode, GlyphColor))
+	if highlighted {
+		b.WriteString(bold + underline)
+	}
+	b.WriteString(" " + string(glyph) + " ")
+	b.WriteString(reset)
+	return b.String()
+}
+
+func asciiGlyph(table *codec.Codec, code int) rune {
+	if r, ok := table.Glyph(code); ok {
+		return r
+	}
+	if r, ok := asciiTiles[code]; ok {
+		return r
+	}
+	return '?'
+}
+
+func contrast(c RGB) RGB {
+	if int(c.R)*299+int(c.G)*587+int(c.B)*114 > 128000 {
+		return RGB{0, 0, 0}
+	}
+	return RGB{255, 255, 255}
+}
+
+func background(mode Mode, c RGB) string {
+	switch mode {
+	case ModeTrueColor:
+		return fmt.Sprintf("\x1b[48;2;%d;%d;%dm", c.R, c.G, c.B)
+	case Mode256:
+		return fmt.Sprintf("\x1b[48;5;%dm", to256(c))
+	default:
+		return fmt.Sprintf("\x1b[%dm", 40+to16(c)%8+60*(to16(c)/8))
+	}
+}
+
+func foreground(mode Mode, c RGB) string {
+	switch mode {
+	case ModeTrueColor:
+		return fmt.Sprintf("\x1b[38;2;%d;%d;%dm", c.R, c.G, c.B)
+	case Mode256:
+		return fmt.Sprintf("\x1b[38;5;%dm", to256(c))
+	default:
+		return fmt.Sprintf("\x1b[%dm", 30+to16(c)%8+60*(to16(c)/8))
+	}
+}
+
+func to256(c RGB) int {
+	level := func(v uint8) int {
+		if v < 48 {
+			return 0
+		}
+		if v < 115 {
+			return 1
+		}
+		return (int(v) - 35) / 40
+	}
+	r, g, b := level(c.R), level(c.G), level(c.B)
+	cube := 16 + 36*r + 6*g + b
+
+	steps := [6]int{0, 95, 135, 175, 215, 255}
+	cubeDistance := distance(c, RGB{uint8(steps[r]), uint8(steps[g]), uint8(steps[b])})
+
+	average := (int(c.R) + int(c.G) + int(c.B)) / 3
+	grey := (average - 3) / 10
+	if grey < 0 {
+		grey = 0
+	}
+	if grey > 23 {
+		grey = 23
+	}
+	greyValue := uint8(8 + grey*10)
+	if distance(c, RGB{greyValue, greyValue, greyValue}) < cubeDistance {
+		return 232 + grey
+	}
+	return cube
+}
+
+var ansi16 = [16]RGB{
+	{0, 0, 0}, {170, 0, 0}, {0, 170, 0}, {170, 85, 0},
+	{0, 0, 170}, {170, 0, 170}, {0, 170, 170}, {170, 170, 170},
+	{85, 85, 85}, {255, 85, 85}, {85, 255, 85}, {255, 255, 85},
+	{85, 85, 255}, {255, 85, 255}, {85, 255, 255}, {255, 255, 255},
+}
+
+func to16(c RGB) int {
+	best, bestDistance := 0, -1
+	for i, candidate := range ansi16 {
+		if d := distance(c, candidate); bestDistance < 0 || d < bestDistance {
+			best, bestDistance = i, d
+		}
+	}
+	return best
+}
+
+func distance(a, b RGB) int {
+	dr := int(a.R) - int(b.R)
+	dg := int(a.G) - int(b.G)
+	db := int(a.B) - int(b.B)
+	return dr*dr + dg*dg + db*db
+}
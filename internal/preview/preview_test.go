@@ -0,0 +1,96 @@
+package preview
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"vbcli/internal/codec"
+)
+
+func TestRenderASCII(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	if err := Render(&out, [][]int{{8, 9}, {codec.Red, 0}}, Options{Mode: ModeASCII}); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	want := "+------+\n| H  I |\n| r    |\n+------+\n"
+	if out.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestRenderASCIIHighlight(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	highlight := func(row, column int) bool { return row == 0 && column == 1 }
+	if err := Render(&out, [][]int{{8, 9}}, Options{Mode: ModeASCII, Highlight: highlight}); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(out.String(), "| H [I]|") {
+		t.Fatalf("expected highlighted cell, got:\n%s", out.String())
+	}
+}
+
+func TestRenderTrueColor(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	if err := Render(&out, [][]int{{codec.Green, 1}}, Options{Mode: ModeTrueColor}); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(out.String(), "\x1b[48;2;0;154;68m   ") {
+		t.Fatalf("expected green true-colour tile, got %q", out.String())
+	}
+	if !strings.Contains(out.String(), " A ") {
+		t.Fatalf("expected glyph, got %q", out.String())
+	}
+}
+
+func TestRenderFallbackPalettes(t *testing.T) {
+	t.Parallel()
+
+	var out256, out16 bytes.Buffer
+	if err := Render(&out256, [][]int{{codec.Red}}, Options{Mode: Mode256}); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(out256.String(), "\x1b[48;5;") {
+		t.Fatalf("expected 256-colour escape, got %q", out256.String())
+	}
+	if err := Render(&out16, [][]int{{codec.Red}}, Options{Mode: Mode16}); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if strings.Contains(out16.String(), "48;5;") || strings.Contains(out16.String(), "48;2;") {
+		t.Fatalf("expected only 16-colour escapes, got %q", out16.String())
+	}
+}
+
+func TestDetectMode(t *testing.T) {
+	t.Parallel()
+
+	env := func(values map[string]string) func(string) string {
+		return func(key string) string { return values[key] }
+	}
+	if got := DetectMode(&bytes.Buffer{}, env(map[string]string{"COLORTERM": "truecolor"})); got != ModeASCII {
+		t.Fatalf("non-terminal writer should use ASCII, got %v", got)
+	}
+	if got, err := ParseMode("256", &bytes.Buffer{}); err != nil || got != Mode256 {
+		t.Fatalf("ParseMode(256) = %v, %v", got, err)
+	}
+	if _, err := ParseMode("sepia", &bytes.Buffer{}); err == nil {
+		t.Fatal("expected error for unknown mode")
+	}
+}
+
+func TestTo256(t *testing.T) {
+	t.Parallel()
+
+	if got := to256(RGB{255, 255, 255}); got != 231 {
+		t.Fatalf("white = %d, want 231", got)
+	}
+	if got := to256(RGB{34, 34, 34}); got < 232 {
+		t.Fatalf("dark grey should use the grey ramp, got %d", got)
+	}
+}
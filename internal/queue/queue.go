@@ -0,0 +1,215 @@
+// Package queue keeps a priority queue of messages in the state directory.
+// Producers add items with a priority and an expiry; a daemon shows the
+// highest-priority item that has not expired. Every change is made under
+// the store's lock, so several processes can share the queue.
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"vbcli/internal/state"
+)
+
+const fileName = "queue.json"
+
+// Priorities, lowest first.
+const (
+	PriorityLow = iota + 1
+	PriorityNormal
+	PriorityHigh
+	PriorityUrgent
+)
+
+var priorityNames = []string{"", "low", "normal", "high", "urgent"}
+
+// ParsePriority accepts low, normal, high or urgent.
+func ParsePriority(name string) (int, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for p := PriorityLow; p <= PriorityUrgent; p++ {
+		if priorityNames[p] == name {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid priority %q (expected low, normal, high or urgent)", name)
+}
+
+// PriorityName returns the name of p, or its number if it has none.
+func PriorityName(p int) string {
+	if p >= PriorityLow && p <= PriorityUrgent {
+		return priorityNames[p]
+	}
+	return strconv.Itoa(p)
+}
+
+// Item is one queued message.
+type Item struct {
+	ID         string    `json:"id"`
+	Priority   int       `json:"priority"`
+	EnqueuedAt time.Time `json:"enqueuedAt"`
+	// ExpiresAt is zero for items that stay until they are dropped.
+	ExpiresAt  time.Time `json:"expiresAt,omitzero"`
+	Source     string    `json:"source,omitempty"`
+	Model      string    `json:"model,omitempty"`
+	Characters [][]int   `json:"characters"`
+}
+
+// Expired reports whether the item has expired at now.
+func (i Item) Expired(now time.Time) bool {
+	return !i.ExpiresAt.IsZero() && !now.Before(i.ExpiresAt)
+}
+
+type file struct {
+	Items []Item `json:"items"`
+}
+
+// Queue is the queue stored in one state directory.
+type Queue struct {
+	store *state.Store
+}
+
+// New returns the queue kept in store.
+func New(store *state.Store) *Queue {
+	return &Queue{store: store}
+}
+
+// Add stores item, assigning it an ID and its enqueue time, and returns it.
+func (q *Queue) Add(ctx context.Context, item Item, now time.Time) (Item, error) {
+	err := q.update(ctx, func(items []Item) ([]Item, error) {
+		item.EnqueuedAt = now.UTC()
+		// IDs come from the enqueue time; items added in the same
+		// nanosecond get the next free one.
+		for n := now.UnixNano(); ; n++ {
+			item.ID = strconv.FormatInt(n, 36)
+			if indexOf(items, item.ID) < 0 {
+				break
+			}
+		}
+		return append(items, item), nil
+	})
+	return item, err
+}
+
+// Drop removes the items with the given IDs. If any ID is unknown, nothing
+// is removed.
+func (q *Queue) Drop(ctx context.Context, ids []string) error {
+	return q.update(ctx, func(items []Item) ([]Item, error) {
+		for _, id := range ids {
+			if indexOf(items, id) < 0 {
+				return nil, fmt.Errorf("no queued item %q", id)
+			}
+		}
+		kept := items[:0]
+		for _, item := range items {
+			if !contains(ids, item.ID) {
+				kept = append(kept, item)
+			}
+		}
+		return kept, nil
+	})
+}
+
+// Clear removes every item and returns how many there were.
+func (q *Queue) Clear(ctx context.Context) (int, error) {
+	var removed int
+	err := q.update(ctx, func(items []Item) ([]Item, error) {
+		removed = len(items)
+		return nil, nil
+	})
+	return removed, err
+}
+
+// Prune removes items that have expired at now and returns the rest in the
+// order they are shown.
+func (q *Queue) Prune(ctx context.Context, now time.Time) ([]Item, error) {
+	var active []Item
+	err := q.update(ctx, func(items []Item) ([]Item, error) {
+		active = unexpired(items, now)
+		return active, nil
+	})
+	return active, err
+}
+
+// List returns the items that have not expired at now, in the order they
+// are shown, without changing the queue.
+func (q *Queue) List(now time.Time) ([]Item, error) {
+	items, err := q.read()
+	if err != nil {
+		return nil, err
+	}
+	return unexpired(items, now), nil
+}
+
+func (q *Queue) read() ([]Item, error) {
+	var f file
+	if err := q.store.ReadJSON(fileName, &f); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read queue: %w", err)
+	}
+	return f.Items, nil
+}
+
+func (q *Queue) update(ctx context.Context, fn func([]Item) ([]Item, error)) error {
+	unlock, err := q.store.Lock(ctx, fileName)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	items, err := q.read()
+	if err != nil {
+		return err
+	}
+	items, err = fn(items)
+	if err != nil {
+		return err
+	}
+	if items == nil {
+		items = []Item{}
+	}
+	if err := q.store.WriteJSON(fileName, file{Items: items}); err != nil {
+		return fmt.Errorf("save queue: %w", err)
+	}
+	return nil
+}
+
+// unexpired sorts highest priority first, and oldest first within a
+// priority.
+func unexpired(items []Item, now time.Time) []Item {
+	active := []Item{}
+	for _, item := range items {
+		if !item.Expired(now) {
+			active = append(active, item)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		if active[i].Priority != active[j].Priority {
+			return active[i].Priority > active[j].Priority
+		}
+		return active[i].EnqueuedAt.Before(active[j].EnqueuedAt)
+	})
+	return active
+}
+
+func indexOf(items []Item, id string) int {
+	for i, item := range items {
+		if item.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func contains(ids []string, id string) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package queue
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"vbcli/internal/state"
+)
+
+func openQueue(t *testing.T) *Queue {
+	t.Helper()
+	store, err := state.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("open state: %v", err)
+	}
+	return New(store)
+}
+
+func ids(items []Item) []string {
+	out := []string{}
+	for _, item := range items {
+		out = append(out, item.ID)
+	}
+	return out
+}
+
+func TestQueueOrderAndExpiry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	q := openQueue(t)
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	add := func(priority int, ttl time.Duration, at time.Time) string {
+		item := Item{Priority: priority, Characters: [][]int{{priority}}}
+		if ttl > 0 {
+			item.ExpiresAt = at.Add(ttl)
+		}
+		added, err := q.Add(ctx, item, at)
+		if err != nil {
+			t.Fatalf("add: %v", err)
+		}
+		return added.ID
+	}
+	low := add(PriorityLow, 0, now)
+	normal := add(PriorityNormal, time.Hour, now)
+	urgent := add(PriorityUrgent, 15*time.Minute, now)
+	// Same priority and time: the ID still has to be unique.
+	normal2 := add(PriorityNormal, 0, now)
+	if normal2 == normal {
+		t.Fatalf("duplicate ID %q", normal)
+	}
+
+	items, err := q.List(now)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if want := []string{urgent, normal, normal2, low}; !reflect.DeepEqual(ids(items), want) {
+		t.Fatalf("order %v, want %v", ids(items), want)
+	}
+
+	later := now.Add(30 * time.Minute)
+	if items, _ = q.List(later); !reflect.DeepEqual(ids(items), []string{normal, normal2, low}) {
+		t.Fatalf("after expiry %v", ids(items))
+	}
+	// List does not remove anything; Prune does.
+	if items, err = q.Prune(ctx, later); err != nil || len(items) != 3 {
+		t.Fatalf("prune: %v %v", ids(items), err)
+	}
+	if items, _ = q.List(now); len(items) != 3 {
+		t.Fatalf("expired item survived prune: %v", ids(items))
+	}
+
+	if err := q.Drop(ctx, []string{low, "missing"}); err == nil {
+		t.Fatal("expected error for an unknown ID")
+	}
+	if items, _ = q.List(later); len(items) != 3 {
+		t.Fatal("a failed drop must not remove anything")
+	}
+	if err := q.Drop(ctx, []string{low}); err != nil {
+		t.Fatalf("drop: %v", err)
+	}
+	if n, err := q.Clear(ctx); err != nil || n != 2 {
+		t.Fatalf("clear: %d %v", n, err)
+	}
+	if items, _ = q.List(later); len(items) != 0 {
+		t.Fatalf("queue not empty: %v", ids(items))
+	}
+}
+
+func TestQueueConcurrentAdds(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each writer opens its own store, like separate processes.
+			store, err := state.Open(dir)
+			if err != nil {
+				t.Errorf("open state: %v", err)
+				return
+			}
+			if _, err := New(store).Add(context.Background(), Item{Priority: PriorityNormal}, now); err != nil {
+				t.Errorf("add: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	store, _ := state.Open(dir)
+	items, err := New(store).List(now)
+	if err != nil || len(items) != 20 {
+		t.Fatalf("got %d items, want 20 (%v)", len(items), err)
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	t.Parallel()
+
+	for p := PriorityLow; p <= PriorityUrgent; p++ {
+		got, err := ParsePriority(PriorityName(p))
+		if err != nil || got != p {
+			t.Fatalf("round trip of %d: %d %v", p, got, err)
+		}
+	}
+	if got, err := ParsePriority(" High "); err != nil || got != PriorityHigh {
+		t.Fatalf("got %d %v", got, err)
+	}
+	if _, err := ParsePriority("critical"); err == nil {
+		t.Fatal("expected error for an unknown priority")
+	}
+}
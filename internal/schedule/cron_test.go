@@ -0,0 +1,75 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	t.Parallel()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	// Friday 16 October 2026, 10:07:30 UTC.
+	base := time.Date(2026, 10, 16, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{name: "every minute", spec: "* * * * *", from: base, want: time.Date(2026, 10, 16, 10, 8, 0, 0, time.UTC)},
+		{name: "step", spec: "*/15 * * * *", from: base, want: time.Date(2026, 10, 16, 10, 15, 0, 0, time.UTC)},
+		{name: "exact time is excluded", spec: "8 10 * * *", from: time.Date(2026, 10, 16, 10, 8, 0, 0, time.UTC), want: time.Date(2026, 10, 17, 10, 8, 0, 0, time.UTC)},
+		{name: "weekday names skip the weekend", spec: "0 9 * * mon-fri", from: base, want: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+		{name: "sunday as seven", spec: "30 6 * * 7", from: base, want: time.Date(2026, 10, 18, 6, 30, 0, 0, time.UTC)},
+		{name: "list and range", spec: "0 8,20 1-3 * *", from: base, want: time.Date(2026, 11, 1, 8, 0, 0, 0, time.UTC)},
+		{name: "month name", spec: "0 0 1 jan *", from: base, want: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "day of month or week", spec: "0 12 20 * mon", from: base, want: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)},
+		{name: "macro", spec: "@monthly", from: base, want: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "leap day", spec: "0 0 29 2 *", from: base, want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "never", spec: "0 0 30 2 *", from: base},
+		// 02:30 does not exist on 8 March 2026 in New York.
+		{name: "skipped by daylight saving", spec: "30 2 * * *", from: time.Date(2026, 3, 7, 12, 0, 0, 0, newYork), want: time.Date(2026, 3, 9, 2, 30, 0, 0, newYork)},
+		// 01:30 happens twice on 1 November 2026 in New York.
+		{name: "repeated by daylight saving", spec: "30 1 * * *", from: time.Date(2026, 11, 1, 1, 45, 0, 0, newYork), want: time.Date(2026, 11, 2, 1, 30, 0, 0, newYork)},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cron, err := ParseCron(tc.spec)
+			if err != nil {
+				t.Fatalf("parse %q: %v", tc.spec, err)
+			}
+			if got := cron.Next(tc.from); !got.Equal(tc.want) {
+				t.Fatalf("Next(%s) = %s, want %s", tc.from, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * * funday",
+		"@often",
+	} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+// Package schedule reads schedule files, which list messages to send at
+// times given by cron expressions, and works out when each entry fires.
+package schedule
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schedule is a parsed schedule file.
+type Schedule struct {
+	// TimeZone applies to entries that do not set their own. Empty means
+	// the local time zone.
+	TimeZone string   `yaml:"timezone"`
+	Entries  []*Entry `yaml:"entries"`
+}
+
+// Entry is one scheduled message. Exactly one of Message and Characters is
+// set.
+type Entry struct {
+	Name       string      `yaml:"name"`
+	Cron       string      `yaml:"cron"`
+	TimeZone   string      `yaml:"timezone"`
+	Message    string      `yaml:"message"`
+	Characters [][]int     `yaml:"characters"`
+	Model      string      `yaml:"model"`
+	Align      string      `yaml:"align"`
+	Justify    string      `yaml:"justify"`
+	Transition *Transition `yaml:"transition"`
+	// Priority decides which entry is sent when several fire at the same
+	// minute; the highest wins and ties go to the entry listed first.
+	Priority int `yaml:"priority"`
+
+	cron     Cron
+	location *time.Location
+}
+
+// Transition is set on the board before the entry's message is sent.
+type Transition struct {
+	Type  string `yaml:"type"`
+	Speed string `yaml:"speed"`
+}
+
+// Slot is a minute at which one or more entries fire, ordered by priority.
+// Only the first entry is sent.
+type Slot struct {
+	Time    time.Time
+	Entries []*Entry
+}
+
+// Load reads and parses a schedule file.
+func Load(path string) (*Schedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read schedule: %w", err)
+	}
+	schedule, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return schedule, nil
+}
+
+// Parse parses a schedule from YAML. Unknown keys are errors, so that a
+// misspelt field does not silently change what is sent.
+func Parse(data []byte) (*Schedule, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var schedule Schedule
+	if err := decoder.Decode(&schedule); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse schedule: %w", err)
+	}
+	if err := schedule.init(); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (s *Schedule) init() error {
+	if len(s.Entries) == 0 {
+		return errors.New("schedule has no entries")
+	}
+	defaultLocation, err := loadLocation(s.TimeZone)
+	if err != nil {
+		return err
+	}
+	names := make(map[string]bool, len(s.Entries))
+	for i, entry := range s.Entries {
+		if entry == nil {
+			return fmt.Errorf("entry %d is empty", i+1)
+		}
+		if entry.Name == "" {
+			entry.Name = fmt.Sprintf("entry-%d", i+1)
+		}
+		if names[entry.Name] {
+			return fmt.Errorf("duplicate entry name %q", entry.Name)
+		}
+		names[entry.Name] = true
+
+		if (entry.Message == "") == (len(entry.Characters) == 0) {
+			return fmt.Errorf("entry %q: set exactly one of message and characters", entry.Name)
+		}
+		if entry.cron, err = ParseCron(entry.Cron); err != nil {
+			return fmt.Errorf("entry %q: %w", entry.Name, err)
+		}
+		entry.location = defaultLocation
+		if entry.TimeZone != "" {
+			if entry.location, err = loadLocation(entry.TimeZone); err != nil {
+				return fmt.Errorf("entry %q: %w", entry.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", name, err)
+	}
+	return location, nil
+}
+
+// Location returns the time zone the entry's cron expression is evaluated
+// in.
+func (e *Entry) Location() *time.Location {
+	return e.location
+}
+
+// Next returns the first time after t at which the entry fires, in the
+// entry's time zone.
+func (e *Entry) Next(t time.Time) time.Time {
+	return e.cron.Next(t.In(e.location))
+}
+
+// Next returns the earliest slot after t. It reports false when no entry
+// fires within the next few years.
+func (s *Schedule) Next(t time.Time) (Slot, bool) {
+	var slot Slot
+	for _, entry := range s.Entries {
+		next := entry.Next(t)
+		switch {
+		case next.IsZero():
+		case slot.Entries == nil || next.Before(slot.Time):
+			slot = Slot{Time: next, Entries: []*Entry{entry}}
+		case next.Equal(slot.Time):
+			slot.Entries = append(slot.Entries, entry)
+		}
+	}
+	sort.SliceStable(slot.Entries, func(i, j int) bool {
+		return slot.Entries[i].Priority > slot.Entries[j].Priority
+	})
+	return slot, slot.Entries != nil
+}
+
+// Upcoming returns up to n slots after t.
+func (s *Schedule) Upcoming(t time.Time, n int) []Slot {
+	var slots []Slot
+	for len(slots) < n {
+		slot, ok := s.Next(t)
+		if !ok {
+			break
+		}
+		slots = append(slots, slot)
+		t = slot.Time
+	}
+	return slots
+}
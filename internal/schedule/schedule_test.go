@@ -0,0 +1,94 @@
+package schedule
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSchedule = `
+timezone: UTC
+entries:
+  - name: standup
+    cron: "0 9 * * mon-fri"
+    message: "Standup in the big room"
+    priority: 5
+  - name: lunch
+    cron: "0 12 * * *"
+    timezone: Asia/Tokyo
+    characters: [[8, 9]]
+  - name: fire-drill
+    cron: "0 9 16 10 *"
+    message: "Fire drill"
+    priority: 10
+    transition:
+      type: wave
+      speed: fast
+`
+
+func TestParseSchedule(t *testing.T) {
+	t.Parallel()
+
+	schedule, err := Parse([]byte(testSchedule))
+	if err != nil {
+		t.Skipf("parse (time zone database may be unavailable): %v", err)
+	}
+	if len(schedule.Entries) != 3 || schedule.Entries[2].Transition.Type != "wave" {
+		t.Fatalf("unexpected schedule: %+v", schedule)
+	}
+
+	// Friday 16 October 2026, 02:00 UTC.
+	from := time.Date(2026, 10, 16, 2, 0, 0, 0, time.UTC)
+	slots := schedule.Upcoming(from, 3)
+	if len(slots) != 3 {
+		t.Fatalf("got %d slots", len(slots))
+	}
+	// Noon in Tokyo is 03:00 UTC.
+	if !slots[0].Time.Equal(time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)) || slots[0].Entries[0].Name != "lunch" {
+		t.Fatalf("unexpected first slot: %s %s", slots[0].Time, slots[0].Entries[0].Name)
+	}
+	if slots[0].Time.Location().String() != "Asia/Tokyo" {
+		t.Fatalf("slot time should be in the entry's zone, got %s", slots[0].Time.Location())
+	}
+	second := slots[1]
+	if len(second.Entries) != 2 || second.Entries[0].Name != "fire-drill" || second.Entries[1].Name != "standup" {
+		t.Fatalf("expected the higher priority entry first, got %+v", second.Entries)
+	}
+	if !slots[2].Time.Equal(time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected third slot: %s", slots[2].Time)
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"empty":        ``,
+		"unknown key":  "entries:\n  - cron: '* * * * *'\n    mesage: hi\n",
+		"no message":   "entries:\n  - cron: '* * * * *'\n",
+		"both":         "entries:\n  - cron: '* * * * *'\n    message: hi\n    characters: [[1]]\n",
+		"bad cron":     "entries:\n  - cron: 'often'\n    message: hi\n",
+		"bad zone":     "timezone: Mars/Olympus\nentries:\n  - cron: '* * * * *'\n    message: hi\n",
+		"duplicate":    "entries:\n  - name: a\n    cron: '* * * * *'\n    message: hi\n  - name: a\n    cron: '* * * * *'\n    message: ho\n",
+		"invalid yaml": "entries: [",
+	}
+	for name, input := range tests {
+		if _, err := Parse([]byte(input)); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoadReportsPath(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "schedule.yaml")
+	if err := os.WriteFile(path, []byte("entries: []\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Fatalf("expected error naming the file, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Journal is an append-only JSON Lines file. When it grows past MaxBytes it
+// is rotated to name.1, name.2 and so on, keeping at most Keep old files.
+// Appends from several processes are serialised with a lock file.
+type Journal struct {
+	store    *Store
+	name     string
+	maxBytes int64
+	keep     int
+}
+
+// Journal returns the journal stored in the named file.
+func (s *Store) Journal(name string, maxBytes int64, keep int) *Journal {
+	return &Journal{store: s, name: name, maxBytes: maxBytes, keep: keep}
+}
+
+// Append writes v as one JSON line, rotating the journal first if it is full.
+func (j *Journal) Append(ctx context.Context, v any) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode %s entry: %w", j.name, err)
+	}
+	line = append(line, '\n')
+
+	unlock, err := j.store.Lock(ctx, j.name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := j.rotate(int64(len(line))); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(j.store.Path(j.name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", j.name, err)
+	}
+	if _, err := file.Write(line); err != nil {
+		file.Close()
+		return fmt.Errorf("append to %s: %w", j.name, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("append to %s: %w", j.name, err)
+	}
+	return nil
+}
+
+func (j *Journal) rotate(incoming int64) error {
+	info, err := os.Stat(j.store.Path(j.name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", j.name, err)
+	}
+	if info.Size() == 0 || info.Size()+incoming <= j.maxBytes {
+		return nil
+	}
+	if j.keep < 1 {
+		return j.store.Remove(j.name)
+	}
+	for i := j.keep - 1; i >= 1; i-- {
+		from := j.store.Path(j.name + "." + strconv.Itoa(i))
+		to := j.store.Path(j.name + "." + strconv.Itoa(i+1))
+		if err := os.Rename(from, to); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("rotate %s: %w", j.name, err)
+		}
+	}
+	if err := os.Rename(j.store.Path(j.name), j.store.Path(j.name+".1")); err != nil {
+		return fmt.Errorf("rotate %s: %w", j.name, err)
+	}
+	return nil
+}
+
+// Read calls fn with every line, oldest first, including rotated files.
+// Lines that are not valid JSON, such as a write cut short by a crash, are
+// skipped.
+func (j *Journal) Read(fn func(line []byte) error) error {
+	names := make([]string, 0, j.keep+1)
+	for i := j.keep; i >= 1; i-- {
+		names = append(names, j.name+"."+strconv.Itoa(i))
+	}
+	names = append(names, j.name)
+
+	for _, name := range names {
+		data, err := os.ReadFile(j.store.Path(name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("read %s: %w", name, err)
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 || !json.Valid(line) {
+				continue
+			}
+			if err := fn(line); err != nil {
+				return err
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("read %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"testing"
+)
+
+func readJournal(t *testing.T, journal *Journal) []int {
+	t.Helper()
+	var got []int
+	err := journal.Read(func(line []byte) error {
+		var n int
+		if err := json.Unmarshal(line, &n); err != nil {
+			return err
+		}
+		got = append(got, n)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	return got
+}
+
+func TestJournalRotation(t *testing.T) {
+	t.Parallel()
+
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	// Every entry is two bytes ("N\n"), so each file holds three entries
+	// and the oldest file, 0-2, has been rotated away.
+	journal := store.Journal("log.jsonl", 6, 2)
+	for i := 0; i < 10; i++ {
+		if err := journal.Append(context.Background(), i); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	got := readJournal(t, journal)
+	want := []int{3, 4, 5, 6, 7, 8, 9}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+	if _, err := os.Stat(store.Path("log.jsonl.3")); !os.IsNotExist(err) {
+		t.Fatalf("expected at most two rotated files, stat err %v", err)
+	}
+}
+
+func TestJournalConcurrentAppendsAndTornLines(t *testing.T) {
+	t.Parallel()
+
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	journal := store.Journal("log.jsonl", 1<<20, 1)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			if err := journal.Append(context.Background(), n); err != nil {
+				t.Errorf("Append: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	file, err := os.OpenFile(store.Path("log.jsonl"), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	_, _ = file.WriteString(`{"torn":`)
+	file.Close()
+
+	got := readJournal(t, journal)
+	if len(got) != 20 {
+		t.Fatalf("got %d entries, want 20: %v", len(got), got)
+	}
+}
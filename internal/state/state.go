@@ -0,0 +1,160 @@
+// Package state manages vbcli's local state directory, which holds the
+// last-sent cache, snapshots, the send journal and queues. Files are
+// written atomically, and read-modify-write cycles that other vbcli
+// processes may race with go through lock files.
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// EnvDir overrides the state directory.
+const EnvDir = "VBCLI_STATE_DIR"
+
+const (
+	lockPoll = 20 * time.Millisecond
+	// staleLock is how old a lock file must be before it is assumed to
+	// belong to a process that died without releasing it.
+	staleLock = 30 * time.Second
+)
+
+// DefaultDir returns $VBCLI_STATE_DIR, $XDG_STATE_HOME/vbcli or
+// ~/.local/state/vbcli, in that order.
+func DefaultDir(getenv func(string) string) (string, error) {
+	if dir := getenv(EnvDir); dir != "" {
+		return dir, nil
+	}
+	if dir := getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "vbcli"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("locate state directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "state", "vbcli"), nil
+}
+
+// Store reads and writes files below one state directory.
+type Store struct {
+	dir string
+}
+
+// Open returns a Store for dir, creating the directory if needed.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create state directory: %w", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Path returns the path of a file in the store.
+func (s *Store) Path(elem ...string) string {
+	return filepath.Join(append([]string{s.dir}, elem...)...)
+}
+
+// ReadJSON decodes the named file into v. A missing file is reported with
+// an error that matches os.ErrNotExist.
+func (s *Store) ReadJSON(name string, v any) error {
+	data, err := os.ReadFile(s.Path(name))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decode %s: %w", name, err)
+	}
+	return nil
+}
+
+// WriteJSON encodes v into the named file. The file is replaced atomically,
+// so readers never see a partial write.
+func (s *Store) WriteJSON(name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", name, err)
+	}
+	return s.WriteFile(name, append(data, '\n'))
+}
+
+// WriteFile atomically replaces the named file with data.
+func (s *Store) WriteFile(name string, data []byte) error {
+	path := s.Path(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create state directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Remove deletes the named file. Removing a missing file is not an error.
+func (s *Store) Remove(name string) error {
+	if err := os.Remove(s.Path(name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove %s: %w", name, err)
+	}
+	return nil
+}
+
+// Lock takes an exclusive lock named after a file in the store, waiting
+// until it is free or ctx is done. Call the returned function to release it.
+// Locks older than 30 seconds are treated as abandoned and broken.
+func (s *Store) Lock(ctx context.Context, name string) (func(), error) {
+	path := s.Path(name + ".lock")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("create state directory: %w", err)
+	}
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_, _ = file.WriteString(strconv.Itoa(os.Getpid()))
+			_ = file.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("lock %s: %w", name, err)
+		}
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > staleLock {
+			breakStaleLock(path)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("lock %s: %w", name, ctx.Err())
+		case <-time.After(lockPoll):
+		}
+	}
+}
+
+// breakStaleLock renames the lock aside before removing it, and puts it back
+// if it turns out to be fresh, so breaking a lock never removes one that
+// another process has just taken.
+func breakStaleLock(path string) {
+	aside := fmt.Sprintf("%s.%d.%d", path, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(path, aside); err != nil {
+		return
+	}
+	if info, err := os.Stat(aside); err == nil && time.Since(info.ModTime()) <= staleLock {
+		// The lock was retaken after it was found stale; put it back.
+		_ = os.Link(aside, path)
+	}
+	_ = os.Remove(aside)
+}
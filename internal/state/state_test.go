@@ -0,0 +1,155 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDefaultDir(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{}
+	getenv := func(key string) string { return env[key] }
+
+	env["XDG_STATE_HOME"] = "/xdg"
+	if got, err := DefaultDir(getenv); err != nil || got != filepath.Join("/xdg", "vbcli") {
+		t.Fatalf("XDG: got %q, %v", got, err)
+	}
+	env[EnvDir] = "/custom"
+	if got, err := DefaultDir(getenv); err != nil || got != "/custom" {
+		t.Fatalf("override: got %q, %v", got, err)
+	}
+}
+
+func TestReadWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	store, err := Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	var missing map[string]int
+	if err := store.ReadJSON("nested/data.json", &missing); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected not-exist error, got %v", err)
+	}
+	if err := store.WriteJSON("nested/data.json", map[string]int{"a": 1}); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	var got map[string]int
+	if err := store.ReadJSON("nested/data.json", &got); err != nil || got["a"] != 1 {
+		t.Fatalf("ReadJSON: %v %v", got, err)
+	}
+	entries, err := os.ReadDir(store.Path("nested"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("temporary files left behind: %v %v", entries, err)
+	}
+	if err := store.Remove("nested/data.json"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := store.Remove("nested/data.json"); err != nil {
+		t.Fatalf("Remove missing: %v", err)
+	}
+}
+
+func TestLockSerialisesWriters(t *testing.T) {
+	t.Parallel()
+
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		holders int
+		maxSeen int
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := store.Lock(context.Background(), "counter")
+			if err != nil {
+				t.Errorf("Lock: %v", err)
+				return
+			}
+			mu.Lock()
+			holders++
+			maxSeen = max(maxSeen, holders)
+			mu.Unlock()
+			time.Sleep(2 * time.Millisecond)
+			mu.Lock()
+			holders--
+			mu.Unlock()
+			unlock()
+		}()
+	}
+	wg.Wait()
+	if maxSeen != 1 {
+		t.Fatalf("lock held by %d goroutines at once", maxSeen)
+	}
+}
+
+func TestLockHonoursContextAndBreaksStaleLocks(t *testing.T) {
+	t.Parallel()
+
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	unlock, err := store.Lock(context.Background(), "busy")
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	defer unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := store.Lock(ctx, "busy"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+
+	old := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(store.Path("busy.lock"), old, old); err != nil {
+		t.Fatalf("Chtimes: %v", err)
+	}
+	release, err := store.Lock(context.Background(), "busy")
+	if err != nil {
+		t.Fatalf("stale lock not broken: %v", err)
+	}
+	release()
+}
+
+func TestBreakStaleLockKeepsRetakenLock(t *testing.T) {
+	t.Parallel()
+
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	unlock, err := store.Lock(context.Background(), "busy")
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	defer unlock()
+
+	// The lock was found stale and then retaken by another process before
+	// this one got round to breaking it.
+	path := store.Path("busy.lock")
+	breakStaleLock(path)
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("retaken lock was removed: %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("left %d files behind, want only the lock", len(entries))
+	}
+}
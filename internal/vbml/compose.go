@@ -0,0 +1,304 @@
+// Package vbml renders VBML compose requests locally, without the network
+// round trip to vbml.vestaboard.com. Dynamic expressions other than plain
+// props substitution still need the remote renderer.
+package vbml
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"vbcli/internal/codec"
+)
+
+const (
+	FlagshipRows    = 6
+	FlagshipColumns = 22
+	NoteRows        = 3
+	NoteColumns     = 15
+)
+
+// Request mirrors the JSON body accepted by the VBML compose endpoint.
+type Request struct {
+	Props      map[string]string `json:"props,omitempty"`
+	Style      *BoardStyle       `json:"style,omitempty"`
+	Components []Component       `json:"components"`
+}
+
+type BoardStyle struct {
+	Height int `json:"height"`
+	Width  int `json:"width"`
+}
+
+type Component struct {
+	Template      string         `json:"template,omitempty"`
+	RawCharacters [][]int        `json:"rawCharacters,omitempty"`
+	Style         ComponentStyle `json:"style"`
+}
+
+type ComponentStyle struct {
+	Align            string    `json:"align,omitempty"`
+	Justify          string    `json:"justify,omitempty"`
+	Height           int       `json:"height,omitempty"`
+	Width            int       `json:"width,omitempty"`
+	AbsolutePosition *Position `json:"absolutePosition,omitempty"`
+}
+
+type Position struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Renderer formats messages locally. It satisfies vestaboard.Formatter.
+type Renderer struct {
+	Props map[string]string
+}
+
+func (r Renderer) FormatMessage(_ context.Context, message, model, align, justify string) ([][]int, error) {
+	req := Request{
+		Props: r.Props,
+		Components: []Component{{
+			Template: message,
+			Style:    ComponentStyle{Align: align, Justify: justify},
+		}},
+	}
+	if model == "note" {
+		req.Style = &BoardStyle{Height: NoteRows, Width: NoteColumns}
+	}
+	return Compose(req)
+}
+
+// Compose lays out every component on a blank board and returns the
+// resulting characters matrix.
+func Compose(req Request) ([][]int, error) {
+	rows, columns := FlagshipRows, FlagshipColumns
+	if req.Style != nil {
+		if req.Style.Height <= 0 || req.Style.Width <= 0 {
+			return nil, fmt.Errorf("invalid board style %dx%d", req.Style.Height, req.Style.Width)
+		}
+		rows, columns = req.Style.Height, req.Style.Width
+	}
+
+	board := blank(rows, columns)
+	x, y, rowHeight := 0, 0, 0
+	for i, component := range req.Components {
+		height, width := component.Style.Height, component.Style.Width
+		if height <= 0 {
+			height = rows
+		}
+		if width <= 0 {
+			width = columns
+		}
+
+		cells, err := renderComponent(component, req.Props, height, width)
+		if err != nil {
+			return nil, fmt.Errorf("component %d: %w", i, err)
+		}
+
+		if position := component.Style.AbsolutePosition; position != nil {
+			paste(board, cells, position.X, position.Y)
+			continue
+		}
+		// Components without an absolute position flow left to right and
+		// wrap onto the next free row, like inline blocks.
+		if x > 0 && x+width > columns {
+			x = 0
+			y += rowHeight
+			rowHeight = 0
+		}
+		paste(board, cells, x, y)
+		x += width
+		if height > rowHeight {
+			rowHeight = height
+		}
+	}
+	return board, nil
+}
+
+func renderComponent(component Component, props map[string]string, height, width int) ([][]int, error) {
+	if component.RawCharacters != nil {
+		cells := blank(height, width)
+		paste(cells, component.RawCharacters, 0, 0)
+		return cells, nil
+	}
+
+	template, err := substituteProps(component.Template, props)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := wrap(template, width)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+
+	align := strings.ToLower(component.Style.Align)
+	justify := strings.ToLower(component.Style.Justify)
+
+	top := 0
+	switch align {
+	case "", "top":
+	case "center":
+		top = (height - len(lines)) / 2
+	case "bottom":
+		top = height - len(lines)
+	default:
+		return nil, fmt.Errorf("invalid align %q", component.Style.Align)
+	}
+
+	longest := 0
+	for _, line := range lines {
+		if len(line) > longest {
+			longest = len(line)
+		}
+	}
+
+	cells := blank(height, width)
+	for i, line := range lines {
+		left := 0
+		switch justify {
+		case "", "left":
+		case "center":
+			left = (width - len(line)) / 2
+		case "right":
+			left = width - len(line)
+		case "justified":
+			// Lines stay left-aligned with each other while the block as a
+			// whole is centred.
+			left = (width - longest) / 2
+		default:
+			return nil, fmt.Errorf("invalid justify %q", component.Style.Justify)
+		}
+		copy(cells[top+i][left:], line)
+	}
+	return cells, nil
+}
+
+func substituteProps(template string, props map[string]string) (string, error) {
+	var out strings.Builder
+	for {
+		start := strings.Index(template, "{{")
+		if start == -1 {
+			out.WriteString(template)
+			return out.String(), nil
+		}
+		end := strings.Index(template[start+2:], "}}")
+		if end == -1 {
+			out.WriteString(template)
+			return out.String(), nil
+		}
+		end += start + 2
+
+		expression := strings.TrimSpace(template[start+2 : end])
+		name := strings.TrimPrefix(expression, "props.")
+		value, ok := props[name]
+		if !ok {
+			return "", fmt.Errorf("template expression {{%s}} cannot be rendered locally; use the remote renderer", expression)
+		}
+		out.WriteString(template[:start])
+		out.WriteString(value)
+		template = template[end+2:]
+	}
+}
+
+// wrap splits words longer than a line.
+func wrap(template string, width int) ([][]int, error) {
+	if template == "" {
+		return nil, nil
+	}
+
+	var lines [][]int
+	for _, paragraph := range strings.Split(template, "\n") {
+		var current []int
+		started := false
+		for _, word := range strings.Split(paragraph, " ") {
+			codes, err := encodeWord(word)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case !started:
+				started = true
+			case len(current)+1+len(codes) <= width:
+				current = append(current, 0)
+			default:
+				lines = append(lines, current)
+				current = nil
+			}
+			if len(codes) == 0 {
+				continue
+			}
+			for len(current)+len(codes) > width {
+				split := width - len(current)
+				lines = append(lines, append(current, codes[:split]...))
+				current = nil
+				codes = codes[split:]
+			}
+			current = append(current, codes...)
+		}
+		lines = append(lines, current)
+	}
+	return lines, nil
+}
+
+func encodeWord(word string) ([]int, error) {
+	var codes []int
+	for i := 0; i < len(word); {
+		if word[i] == '{' {
+			if end := strings.IndexByte(word[i:], '}'); end > 1 {
+				if code, err := strconv.Atoi(word[i+1 : i+end]); err == nil {
+					if code < 0 || code > codec.MaxCode {
+						return nil, fmt.Errorf("character code {%d} is out of range", code)
+					}
+					codes = append(codes, code)
+					i += end + 1
+					continue
+				}
+			}
+		}
+
+		r, size := utf8.DecodeRuneInString(word[i:])
+		code, ok := codec.Flagship.EncodeRune(r)
+		if !ok {
+			return nil, &UnsupportedCharacterError{Rune: r}
+		}
+		codes = append(codes, code)
+		i += size
+	}
+	return codes, nil
+}
+
+// UnsupportedCharacterError reports a rune with no Vestaboard character code.
+type UnsupportedCharacterError struct {
+	Rune rune
+}
+
+func (e *UnsupportedCharacterError) Error() string {
+	return fmt.Sprintf("character %q has no Vestaboard code", e.Rune)
+}
+
+func blank(rows, columns int) [][]int {
+	board := make([][]int, rows)
+	for i := range board {
+		board[i] = make([]int, columns)
+	}
+	return board
+}
+
+func paste(dst, src [][]int, x, y int) {
+	for r, row := range src {
+		if y+r < 0 || y+r >= len(dst) {
+			continue
+		}
+		for c, code := range row {
+			if x+c < 0 || x+c >= len(dst[y+r]) {
+				continue
+			}
+			dst[y+r][x+c] = code
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package vbml
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// envRecord records the remote compose responses before comparing, e.g.
+// VBCLI_RECORD_VBML=https://vbml.vestaboard.com.
+const envRecord = "VBCLI_RECORD_VBML"
+
+// composeFixture pins the local renderer's output for a compose request.
+// The characters are hand-written, so they catch regressions but say
+// nothing about parity with the remote renderer; that is what the recorded
+// NAME.remote.json next to a fixture is for.
+type composeFixture struct {
+	Request    json.RawMessage `json:"request"`
+	Characters [][]int         `json:"characters"`
+}
+
+func TestComposeFixtures(t *testing.T) {
+	for name, fixture := range readComposeFixtures(t) {
+		t.Run(name, func(t *testing.T) {
+			got, err := composeFixtureRequest(fixture)
+			if err != nil {
+				t.Fatalf("compose: %v", err)
+			}
+			if !reflect.DeepEqual(got, fixture.Characters) {
+				t.Fatalf("local compose changed\n got: %v\nwant: %v", got, fixture.Characters)
+			}
+		})
+	}
+}
+
+// TestComposeMatchesRemote compares the local renderer byte for byte with
+// compose responses recorded from the VBML API. Fixtures without a recorded
+// response are skipped.
+func TestComposeMatchesRemote(t *testing.T) {
+	remote := os.Getenv(envRecord)
+	for name, fixture := range readComposeFixtures(t) {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join("testdata", "compose", name+".remote.json")
+			if remote != "" {
+				recordComposeResponse(t, remote, path, fixture.Request)
+			}
+			want, err := os.ReadFile(path)
+			if os.IsNotExist(err) {
+				t.Skipf("no recorded response; record with %s=https://vbml.vestaboard.com", envRecord)
+			}
+			if err != nil {
+				t.Fatalf("read recorded response: %v", err)
+			}
+
+			characters, err := composeFixtureRequest(fixture)
+			if err != nil {
+				t.Fatalf("compose: %v", err)
+			}
+			got, err := json.Marshal(characters)
+			if err != nil {
+				t.Fatalf("encode characters: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("local compose differs from the recorded response\n got: %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func readComposeFixtures(t *testing.T) map[string]composeFixture {
+	t.Helper()
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "compose", "*.json"))
+	if err != nil {
+		t.Fatalf("glob fixtures: %v", err)
+	}
+	fixtures := make(map[string]composeFixture)
+	for _, path := range paths {
+		if strings.HasSuffix(path, ".remote.json") {
+			continue
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read fixture: %v", err)
+		}
+		var fixture composeFixture
+		if err := json.Unmarshal(raw, &fixture); err != nil {
+			t.Fatalf("decode fixture %s: %v", path, err)
+		}
+		fixtures[strings.TrimSuffix(filepath.Base(path), ".json")] = fixture
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("no compose fixtures found")
+	}
+	return fixtures
+}
+
+func composeFixtureRequest(fixture composeFixture) ([][]int, error) {
+	var req Request
+	if err := json.Unmarshal(fixture.Request, &req); err != nil {
+		return nil, err
+	}
+	return Compose(req)
+}
+
+// recordComposeResponse saves the remote compose response body exactly as
+// received.
+func recordComposeResponse(t *testing.T, remote, path string, request []byte) {
+	t.Helper()
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Post(strings.TrimRight(remote, "/")+"/compose", "application/json", bytes.NewReader(request))
+	if err != nil {
+		t.Fatalf("record response: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("record response: status %d: %s", resp.StatusCode, body)
+	}
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		t.Fatalf("write recorded response: %v", err)
+	}
+}
+
+func TestRendererFormatMessageNote(t *testing.T) {
+	t.Parallel()
+
+	got, err := Renderer{}.FormatMessage(context.Background(), "HI", "note", "top", "left")
+	if err != nil {
+		t.Fatalf("format message: %v", err)
+	}
+	if len(got) != NoteRows || len(got[0]) != NoteColumns || got[0][0] != 8 || got[0][1] != 9 {
+		t.Fatalf("unexpected characters: %v", got)
+	}
+}
+
+func TestComposeEmptyTemplateIsBlank(t *testing.T) {
+	t.Parallel()
+
+	got, err := Renderer{}.FormatMessage(context.Background(), "", "flagship", "center", "center")
+	if err != nil {
+		t.Fatalf("format message: %v", err)
+	}
+	if len(got) != FlagshipRows || len(got[0]) != FlagshipColumns {
+		t.Fatalf("unexpected dimensions: %dx%d", len(got), len(got[0]))
+	}
+	for _, row := range got {
+		for _, code := range row {
+			if code != 0 {
+				t.Fatalf("expected blank board, got %v", got)
+			}
+		}
+	}
+}
+
+func TestComposeRejectsUnknownExpressions(t *testing.T) {
+	t.Parallel()
+
+	if _, err := (Renderer{}).FormatMessage(context.Background(), "It is {{now}}", "flagship", "center", "center"); err == nil {
+		t.Fatal("expected error for dynamic expression")
+	}
+}
+
+func TestComposeRejectsUnsupportedCharacters(t *testing.T) {
+	t.Parallel()
+
+	_, err := Renderer{}.FormatMessage(context.Background(), "snow ☃", "flagship", "center", "center")
+	var unsupported *UnsupportedCharacterError
+	if !errors.As(err, &unsupported) || unsupported.Rune != '☃' {
+		t.Fatalf("got %v, want UnsupportedCharacterError for ☃", err)
+	}
+}
+
+func TestComposeTruncatesOverflowingLines(t *testing.T) {
+	t.Parallel()
+
+	got, err := Renderer{}.FormatMessage(context.Background(), "A\nB\nC\nD", "note", "top", "left")
+	if err != nil {
+		t.Fatalf("format message: %v", err)
+	}
+	if got[0][0] != 1 || got[2][0] != 3 {
+		t.Fatalf("unexpected characters: %v", got)
+	}
+}
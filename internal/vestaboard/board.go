@@ -0,0 +1,38 @@
+package vestaboard
+
+import (
+	"context"
+
+	board "vbcli/vestaboard"
+)
+
+var (
+	_ board.Board     = (*Client)(nil)
+	_ board.Board     = (*LocalClient)(nil)
+	_ board.Formatter = (*Client)(nil)
+	_ board.Formatter = (*LocalClient)(nil)
+	_ board.Formatter = (*VBMLClient)(nil)
+)
+
+// VBMLClient talks to the unauthenticated VBML API only. It carries no
+// token and formats messages, but cannot drive a board.
+type VBMLClient struct {
+	client *Client
+}
+
+// NewVBMLClient returns a VBMLClient configured by the same options as
+// NewClient.
+func NewVBMLClient(options ...Option) (*VBMLClient, error) {
+	client, err := newClient(&Client{
+		baseURL: cloudBaseURL,
+		vbmlURL: vbmlBaseURL,
+	}, options)
+	if err != nil {
+		return nil, err
+	}
+	return &VBMLClient{client: client}, nil
+}
+
+func (v *VBMLClient) FormatMessage(ctx context.Context, message, model, align, justify string) ([][]int, error) {
+	return v.client.FormatMessage(ctx, message, model, align, justify)
+}
@@ -0,0 +1,117 @@
+package vestaboard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	board "vbcli/vestaboard"
+)
+
+// GetCurrentState fetches the current message and decodes it into a BoardState.
+func (c *Client) GetCurrentState(ctx context.Context) (*board.BoardState, error) {
+	body, err := c.GetCurrent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBoardState(body)
+}
+
+// ParseBoardState decodes a Cloud API read response. The layout may be
+// either a JSON-encoded string or a plain array, and the creation time may
+// be reported as epoch milliseconds or an RFC 3339 string.
+func ParseBoardState(body []byte) (*board.BoardState, error) {
+	var payload struct {
+		CurrentMessage struct {
+			ID        string          `json:"id"`
+			Layout    json.RawMessage `json:"layout"`
+			Created   json.RawMessage `json:"created"`
+			CreatedAt json.RawMessage `json:"createdAt"`
+		} `json:"currentMessage"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("decode API response: %w", err)
+	}
+
+	message := payload.CurrentMessage
+	layout, err := decodeLayout(message.Layout)
+	if err != nil {
+		return nil, err
+	}
+
+	created := message.CreatedAt
+	if len(created) == 0 {
+		created = message.Created
+	}
+	createdAt, err := decodeTimestamp(created)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, columns := board.Dimensions(layout)
+	return &board.BoardState{
+		ID:        message.ID,
+		CreatedAt: createdAt,
+		Layout:    layout,
+		Model:     board.InferModel(rows, columns),
+		Rows:      rows,
+		Columns:   columns,
+		Raw:       body,
+	}, nil
+}
+
+func decodeLayout(raw json.RawMessage) ([][]int, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, errors.New("currentMessage.layout not found")
+	}
+
+	if raw[0] == '"' {
+		var encoded string
+		if err := json.Unmarshal(raw, &encoded); err != nil {
+			return nil, fmt.Errorf("decode currentMessage.layout: %w", err)
+		}
+		if encoded == "" {
+			return nil, errors.New("currentMessage.layout not found")
+		}
+		raw = json.RawMessage(encoded)
+	}
+
+	var layout [][]int
+	if err := json.Unmarshal(raw, &layout); err != nil {
+		return nil, fmt.Errorf("decode currentMessage.layout: %w", err)
+	}
+	return layout, nil
+}
+
+func decodeTimestamp(raw json.RawMessage) (time.Time, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return time.Time{}, nil
+	}
+
+	if raw[0] == '"' {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return time.Time{}, fmt.Errorf("decode currentMessage.createdAt: %w", err)
+		}
+		if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return time.UnixMilli(millis).UTC(), nil
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("decode currentMessage.createdAt: %w", err)
+		}
+		return parsed, nil
+	}
+
+	millis, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("decode currentMessage.createdAt: %w", err)
+	}
+	return time.UnixMilli(millis).UTC(), nil
+}
@@ -0,0 +1,108 @@
+package vestaboard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	board "vbcli/vestaboard"
+)
+
+func TestParseBoardStateStringLayout(t *testing.T) {
+	t.Parallel()
+
+	row := "[" + strings.TrimSuffix(strings.Repeat("0,", 22), ",") + "]"
+	layout := "[" + strings.TrimSuffix(strings.Repeat(row+",", 6), ",") + "]"
+	body := `{"currentMessage":{"id":"msg-1","layout":"` + layout + `","createdAt":1700000000000}}`
+
+	state, err := ParseBoardState([]byte(body))
+	if err != nil {
+		t.Fatalf("parse board state: %v", err)
+	}
+	if state.ID != "msg-1" {
+		t.Fatalf("id = %q, want %q", state.ID, "msg-1")
+	}
+	if state.Rows != 6 || state.Columns != 22 || state.Model != board.ModelFlagship {
+		t.Fatalf("unexpected dimensions: %dx%d model %q", state.Rows, state.Columns, state.Model)
+	}
+	if !state.CreatedAt.Equal(time.UnixMilli(1700000000000)) {
+		t.Fatalf("createdAt = %v", state.CreatedAt)
+	}
+	if string(state.Raw) != body {
+		t.Fatalf("raw body not preserved")
+	}
+}
+
+func TestParseBoardStateArrayLayoutAndRFC3339(t *testing.T) {
+	t.Parallel()
+
+	body := `{"currentMessage":{"id":"x","layout":[[1,2,3],[4,5,6]],"created":"2025-01-02T03:04:05Z"}}`
+	state, err := ParseBoardState([]byte(body))
+	if err != nil {
+		t.Fatalf("parse board state: %v", err)
+	}
+	if len(state.Layout) != 2 || state.Layout[1][2] != 6 {
+		t.Fatalf("unexpected layout: %#v", state.Layout)
+	}
+	if state.Model != "" {
+		t.Fatalf("model = %q, want empty for unknown dimensions", state.Model)
+	}
+	if want := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC); !state.CreatedAt.Equal(want) {
+		t.Fatalf("createdAt = %v, want %v", state.CreatedAt, want)
+	}
+}
+
+func TestParseBoardStateMissingLayout(t *testing.T) {
+	t.Parallel()
+
+	if _, err := ParseBoardState([]byte(`{"currentMessage":{"id":"x"}}`)); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := ParseBoardState([]byte(`{"currentMessage":{"layout":"not json"}}`)); err == nil {
+		t.Fatal("expected error for malformed layout string")
+	}
+}
+
+func TestGetCurrentState(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"currentMessage":{"id":"abc","layout":"[[1,2],[3,4]]"}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		baseURL:    server.URL,
+		token:      "abc123",
+		httpClient: server.Client(),
+	}
+
+	state, err := client.GetCurrentState(context.Background())
+	if err != nil {
+		t.Fatalf("get current state: %v", err)
+	}
+	if state.ID != "abc" || state.Layout[1][0] != 3 {
+		t.Fatalf("unexpected state: %#v", state)
+	}
+}
+
+func TestParseBoardStateReadsFakeBoard(t *testing.T) {
+	t.Parallel()
+
+	fake := board.NewFakeBoard([][]int{{1, 2}, {3, 4}})
+	state, err := fake.GetCurrentState(context.Background())
+	if err != nil {
+		t.Fatalf("get current state: %v", err)
+	}
+	parsed, err := ParseBoardState(state.Raw)
+	if err != nil {
+		t.Fatalf("raw state should parse like a Cloud response: %v", err)
+	}
+	if parsed.ID != state.ID || parsed.Layout[1][1] != 4 {
+		t.Fatalf("unexpected parsed state: %#v", parsed)
+	}
+}
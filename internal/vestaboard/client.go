@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -19,6 +20,7 @@ const (
 	vbmlBaseURL    = "https://vbml.vestaboard.com"
 	vbmlFormatPath = "/compose"
 	headerName     = "X-vestaboard-token"
+	defaultTimeout = 15 * time.Second
 )
 
 var errMissingToken = errors.New("VESTABOARD_TOKEN is not set")
@@ -30,6 +32,14 @@ type Client struct {
 	httpClient *http.Client
 	verbose    bool
 	logWriter  io.Writer
+	retry      RetryPolicy
+	sleep      func(context.Context, time.Duration) error
+	jitter     func() float64
+	limiter    *Limiter
+	timeout    time.Duration
+	userAgent  string
+	authHeader string
+	service    string
 }
 
 type Option func(*Client)
@@ -41,57 +51,97 @@ func WithVerboseLogging(enabled bool, writer io.Writer) Option {
 	}
 }
 
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	}
+}
+
+func WithVBMLURL(vbmlURL string) Option {
+	return func(c *Client) {
+		c.vbmlURL = strings.TrimRight(strings.TrimSpace(vbmlURL), "/")
+	}
+}
+
+// WithHTTPClient replaces the default HTTP client. A timeout set with
+// WithTimeout is applied to a copy of it, regardless of option order.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = strings.TrimSpace(userAgent)
+	}
+}
+
 func NewClient(token string, options ...Option) (*Client, error) {
 	token = strings.TrimSpace(token)
 	if token == "" {
 		return nil, errMissingToken
 	}
-	client := &Client{
-		baseURL: cloudBaseURL,
-		vbmlURL: vbmlBaseURL,
-		token:   token,
-		httpClient: &http.Client{
-			Timeout: 15 * time.Second,
-		},
-	}
+	return newClient(&Client{
+		baseURL:    cloudBaseURL,
+		vbmlURL:    vbmlBaseURL,
+		token:      token,
+		authHeader: headerName,
+		service:    "vestaboard API",
+	}, options)
+}
 
+func newClient(client *Client, options []Option) (*Client, error) {
+	client.httpClient = &http.Client{Timeout: defaultTimeout}
 	for _, option := range options {
 		option(client)
 	}
+	if err := client.validateURLs(); err != nil {
+		return nil, err
+	}
+	if client.httpClient == nil {
+		client.httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+	if client.timeout > 0 {
+		httpClient := *client.httpClient
+		httpClient.Timeout = client.timeout
+		client.httpClient = &httpClient
+	}
 	return client, nil
 }
 
+func (c *Client) validateURLs() error {
+	for _, setting := range []struct{ name, raw string }{{"API URL", c.baseURL}, {"VBML URL", c.vbmlURL}} {
+		parsed, err := url.Parse(setting.raw)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid %s %q (expected an absolute http or https URL)", setting.name, setting.raw)
+		}
+	}
+	return nil
+}
+
 func (c *Client) SendCharacters(ctx context.Context, characters [][]int) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
 	payload := map[string][][]int{"characters": characters}
 	return c.postMessage(ctx, payload)
 }
 
 func (c *Client) GetCurrent(ctx context.Context) ([]byte, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cloudURL(cloudRootPath), nil)
-	if err != nil {
-		return nil, fmt.Errorf("build request: %w", err)
-	}
-	req.Header.Set(headerName, c.token)
-	req.Header.Set("Accept", "application/json")
-	c.logHTTP("request", req.URL.String(), nil, 0)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read API response: %w", err)
-	}
-	c.logHTTP("response", req.URL.String(), respBody, resp.StatusCode)
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("vestaboard API returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
-	}
-
-	return respBody, nil
+	return c.do(ctx, http.MethodGet, c.cloudURL(cloudRootPath), nil, true)
 }
 
 func (c *Client) SetTransition(ctx context.Context, transitionType, transitionSpeed string) error {
@@ -104,58 +154,12 @@ func (c *Client) SetTransition(ctx context.Context, transitionType, transitionSp
 		return fmt.Errorf("marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.cloudURL(transitionPath), bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("build request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set(headerName, c.token)
-	c.logHTTP("request", req.URL.String(), body, 0)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("read API response: %w", err)
-	}
-	c.logHTTP("response", req.URL.String(), respBody, resp.StatusCode)
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("vestaboard API returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
-	}
-	return nil
+	_, err = c.do(ctx, http.MethodPut, c.cloudURL(transitionPath), body, true)
+	return err
 }
 
 func (c *Client) GetTransition(ctx context.Context) ([]byte, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cloudURL(transitionPath), nil)
-	if err != nil {
-		return nil, fmt.Errorf("build request: %w", err)
-	}
-	req.Header.Set(headerName, c.token)
-	req.Header.Set("Accept", "application/json")
-	c.logHTTP("request", req.URL.String(), nil, 0)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read API response: %w", err)
-	}
-	c.logHTTP("response", req.URL.String(), respBody, resp.StatusCode)
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("vestaboard API returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
-	}
-
-	return respBody, nil
+	return c.do(ctx, http.MethodGet, c.cloudURL(transitionPath), nil, true)
 }
 
 func (c *Client) FormatMessage(ctx context.Context, message, model, align, justify string) ([][]int, error) {
@@ -181,27 +185,9 @@ func (c *Client) FormatMessage(ctx context.Context, message, model, align, justi
 		return nil, fmt.Errorf("marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.vbmlURL+vbmlFormatPath, bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("build request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	c.logHTTP("request", req.URL.String(), body, 0)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	bodyBytes, err := io.ReadAll(resp.Body)
+	bodyBytes, err := c.do(ctx, http.MethodPost, c.vbmlURL+vbmlFormatPath, body, false)
 	if err != nil {
-		return nil, fmt.Errorf("read VBML response: %w", err)
-	}
-	c.logHTTP("response", req.URL.String(), bodyBytes, resp.StatusCode)
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("vbml API returned %s: %s", resp.Status, strings.TrimSpace(string(bodyBytes)))
+		return nil, err
 	}
 
 	var response struct {
@@ -225,35 +211,90 @@ func (c *Client) postMessage(ctx context.Context, payload any) error {
 		return fmt.Errorf("marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cloudURL(cloudRootPath), bytes.NewReader(body))
+	_, err = c.do(ctx, http.MethodPost, c.cloudURL(cloudRootPath), body, true)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Category == CategoryConflict {
+		return nil
+	}
+	return err
+}
+
+// do retries according to the RetryPolicy. Only the message POST is
+// non-idempotent: VBML compose is a pure function of its input.
+func (c *Client) do(ctx context.Context, method, target string, body []byte, authenticated bool) ([]byte, error) {
+	idempotent := method != http.MethodPost || !authenticated
+	for attempt := 1; ; attempt++ {
+		respBody, err := c.doOnce(ctx, method, target, body, authenticated)
+		if err == nil {
+			return respBody, nil
+		}
+		delay, ok := c.retry.nextDelay(attempt, err, idempotent, c.jitter)
+		if !ok {
+			return nil, err
+		}
+		c.logRetry(err, delay, attempt)
+		if err := c.sleepFor(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) doOnce(ctx context.Context, method, target string, body []byte, authenticated bool) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
 	if err != nil {
-		return fmt.Errorf("build request: %w", err)
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req.Header.Set("Accept", "application/json")
+	}
+	if authenticated {
+		authHeader := c.authHeader
+		if authHeader == "" {
+			authHeader = headerName
+		}
+		req.Header.Set(authHeader, c.token)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set(headerName, c.token)
 	c.logHTTP("request", req.URL.String(), body, 0)
 
+	service := c.service
+	if !authenticated {
+		service = "vbml API"
+	} else if service == "" {
+		service = "vestaboard API"
+	}
+	endpoint := method + " " + req.URL.String()
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("send request: %w", err)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("send request: %w", err)
+		}
+		return nil, &APIError{Service: service, Endpoint: endpoint, Category: CategoryNetwork, Err: err}
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("read API response: %w", err)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("read API response: %w", err)
+		}
+		return nil, &APIError{Service: service, Endpoint: endpoint, Category: CategoryNetwork, Err: fmt.Errorf("read API response: %w", err)}
 	}
 	c.logHTTP("response", req.URL.String(), respBody, resp.StatusCode)
 
-	if resp.StatusCode == http.StatusConflict {
-		return nil
-	}
-
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("vestaboard API returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+		return nil, newStatusError(service, endpoint, resp, respBody)
 	}
-
-	return nil
+	return respBody, nil
 }
 
 func (c *Client) logHTTP(direction, url string, payload []byte, statusCode int) {
@@ -270,6 +311,13 @@ func (c *Client) logHTTP(direction, url string, payload []byte, statusCode int)
 	_, _ = fmt.Fprintf(c.logWriter, "response payload:\n%s\n", prettyJSON(payload))
 }
 
+func (c *Client) logRetry(err error, delay time.Duration, attempt int) {
+	if !c.verbose || c.logWriter == nil {
+		return
+	}
+	_, _ = fmt.Fprintf(c.logWriter, "retrying in %s after attempt %d/%d: %v\n", delay, attempt, c.retry.MaxAttempts, err)
+}
+
 func prettyJSON(payload []byte) string {
 	trimmed := bytes.TrimSpace(payload)
 	if len(trimmed) == 0 {
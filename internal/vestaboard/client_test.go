@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewClientMissingToken(t *testing.T) {
@@ -360,3 +361,61 @@ func TestGetTransition(t *testing.T) {
 		t.Fatalf("unexpected body: %s", string(body))
 	}
 }
+
+func TestEndpointOptions(t *testing.T) {
+	t.Parallel()
+
+	var gotAgent, gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAgent = r.Header.Get("User-Agent")
+		gotPath = r.URL.Path
+		_, _ = w.Write([]byte(`{"characters":[[1]]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("abc123",
+		WithTimeout(3*time.Second),
+		WithHTTPClient(server.Client()),
+		WithBaseURL(server.URL+"/proxy/"),
+		WithVBMLURL(server.URL+"/vbml"),
+		WithUserAgent("vbcli-test"),
+	)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	if client.httpClient.Timeout != 3*time.Second {
+		t.Fatalf("timeout = %v, want 3s", client.httpClient.Timeout)
+	}
+	if server.Client().Timeout == 3*time.Second {
+		t.Fatal("WithTimeout must not mutate the caller's http.Client")
+	}
+
+	if err := client.SendCharacters(context.Background(), [][]int{{1}}); err != nil {
+		t.Fatalf("send characters: %v", err)
+	}
+	if gotPath != "/proxy/" || gotAgent != "vbcli-test" {
+		t.Fatalf("path %q, user agent %q", gotPath, gotAgent)
+	}
+
+	if _, err := client.FormatMessage(context.Background(), "hi", "flagship", "center", "center"); err != nil {
+		t.Fatalf("format message: %v", err)
+	}
+	if gotPath != "/vbml/compose" {
+		t.Fatalf("path = %q, want /vbml/compose", gotPath)
+	}
+}
+
+func TestNewClientRejectsInvalidURL(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewClient("abc123", WithBaseURL("cloud.example.com")); err == nil {
+		t.Fatal("expected error for URL without scheme")
+	}
+	// With both URLs invalid, the API URL is always the one reported.
+	for range 10 {
+		_, err := NewClient("abc123", WithBaseURL("cloud.example.com"), WithVBMLURL("vbml.example.com"))
+		if err == nil || !strings.Contains(err.Error(), "invalid API URL") {
+			t.Fatalf("expected the API URL to be reported, got %v", err)
+		}
+	}
+}
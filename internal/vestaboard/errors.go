@@ -0,0 +1,80 @@
+package vestaboard
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrorCategory classifies an APIError by what a caller can do about it.
+type ErrorCategory string
+
+const (
+	CategoryAuth         ErrorCategory = "auth"
+	CategoryRateLimited  ErrorCategory = "rate-limited"
+	CategoryConflict     ErrorCategory = "conflict"
+	CategoryInvalidInput ErrorCategory = "invalid-input"
+	CategoryServer       ErrorCategory = "server"
+	CategoryNetwork      ErrorCategory = "network"
+)
+
+// APIError is returned for non-2xx responses and for transport failures.
+// StatusCode is zero when no response was received.
+type APIError struct {
+	Service    string
+	Endpoint   string
+	StatusCode int
+	Status     string
+	Body       string
+	Header     http.Header
+	Category   ErrorCategory
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("send request: %v", e.Err)
+	}
+	return fmt.Sprintf("%s returned %s: %s", e.Service, e.Status, e.Body)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the same request may succeed if sent again later.
+func (e *APIError) Retryable() bool {
+	switch e.Category {
+	case CategoryRateLimited, CategoryServer, CategoryNetwork:
+		return true
+	default:
+		return false
+	}
+}
+
+func newStatusError(service, endpoint string, resp *http.Response, body []byte) *APIError {
+	return &APIError{
+		Service:    service,
+		Endpoint:   endpoint,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       strings.TrimSpace(string(body)),
+		Header:     resp.Header,
+		Category:   classifyStatus(resp.StatusCode),
+	}
+}
+
+func classifyStatus(statusCode int) ErrorCategory {
+	switch {
+	case statusCode == http.StatusUnauthorized, statusCode == http.StatusForbidden:
+		return CategoryAuth
+	case statusCode == http.StatusTooManyRequests:
+		return CategoryRateLimited
+	case statusCode == http.StatusConflict:
+		return CategoryConflict
+	case statusCode >= 500:
+		return CategoryServer
+	default:
+		return CategoryInvalidInput
+	}
+}
@@ -0,0 +1,76 @@
+package vestaboard
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIErrorCategories(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status int
+		want   ErrorCategory
+	}{
+		{status: http.StatusUnauthorized, want: CategoryAuth},
+		{status: http.StatusForbidden, want: CategoryAuth},
+		{status: http.StatusTooManyRequests, want: CategoryRateLimited},
+		{status: http.StatusBadRequest, want: CategoryInvalidInput},
+		{status: http.StatusServiceUnavailable, want: CategoryServer},
+	}
+
+	for _, tc := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(tc.status)
+			_, _ = w.Write([]byte(" nope \n"))
+		}))
+
+		client := &Client{
+			baseURL:    server.URL,
+			token:      "abc123",
+			httpClient: server.Client(),
+		}
+		_, err := client.GetCurrent(context.Background())
+		server.Close()
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("status %d: expected *APIError, got %T (%v)", tc.status, err, err)
+		}
+		if apiErr.Category != tc.want {
+			t.Fatalf("status %d: category = %q, want %q", tc.status, apiErr.Category, tc.want)
+		}
+		if apiErr.StatusCode != tc.status || apiErr.Body != "nope" {
+			t.Fatalf("status %d: unexpected error fields: %#v", tc.status, apiErr)
+		}
+		if apiErr.Endpoint != "GET "+server.URL+"/" {
+			t.Fatalf("endpoint = %q", apiErr.Endpoint)
+		}
+	}
+}
+
+func TestAPIErrorNetwork(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	client := &Client{
+		baseURL:    url,
+		token:      "abc123",
+		httpClient: &http.Client{},
+	}
+	err := client.SendCharacters(context.Background(), [][]int{{1}})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Category != CategoryNetwork {
+		t.Fatalf("expected network APIError, got %v", err)
+	}
+	if !apiErr.Retryable() {
+		t.Fatal("expected network error to be retryable")
+	}
+}
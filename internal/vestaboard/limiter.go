@@ -0,0 +1,276 @@
+package vestaboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Backends space out message sends by at least these intervals. The Cloud
+// API rejects faster sends as rate limited; the Local API accepts them, but
+// a board needs a few seconds to finish flipping to a message.
+const (
+	CloudSendInterval = 15 * time.Second
+	LocalSendInterval = 5 * time.Second
+)
+
+// ErrSuperseded is returned to a queued send that a coalescing Limiter
+// dropped in favour of a newer one.
+var ErrSuperseded = errors.New("send superseded by a newer message")
+
+// RateLimitError is returned by a fail-fast Limiter when no send slot is free.
+type RateLimitError struct {
+	Wait time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("client rate limit: next send allowed in %s", e.Wait.Round(time.Millisecond))
+}
+
+// Clock is the time source used by a Limiter.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock backed by package time.
+var SystemClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// LimiterConfig configures a Limiter.
+type LimiterConfig struct {
+	// MinInterval is the time it takes to earn one send slot.
+	MinInterval time.Duration
+	// Burst is the number of slots that can accumulate. Defaults to 1.
+	Burst int
+	// FailFast returns a *RateLimitError instead of waiting for a slot.
+	FailFast bool
+	// Coalesce keeps only the newest waiting send; older waiters get
+	// ErrSuperseded and the newest one inherits their slot.
+	Coalesce bool
+	// Clock defaults to the system clock.
+	Clock Clock
+	// Shared, when set, holds the bucket so that Limiters in several
+	// processes space out their sends together.
+	Shared SharedBucket
+}
+
+// Bucket is the state of a Limiter's token bucket. Pending names the
+// coalescing caller waiting for the slot at Ready.
+type Bucket struct {
+	Tokens  float64   `json:"tokens"`
+	Last    time.Time `json:"last"`
+	Pending string    `json:"pending,omitempty"`
+	Ready   time.Time `json:"ready,omitzero"`
+}
+
+// SharedBucket stores a Bucket where several processes can use it. Lock
+// waits for exclusive use of the bucket and returns it, with a zero Last if
+// it has never been used; release saves the bucket and gives it up.
+type SharedBucket interface {
+	Lock(ctx context.Context) (bucket *Bucket, release func() error, err error)
+}
+
+// Limiter is a token bucket that spaces out message sends.
+type Limiter struct {
+	cfg     LimiterConfig
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time
+	pending *limiterWaiter
+}
+
+type limiterWaiter struct {
+	ticket     string
+	ready      time.Time
+	superseded chan struct{}
+	done       bool
+}
+
+func NewLimiter(cfg LimiterConfig) *Limiter {
+	if cfg.Burst <= 0 {
+		cfg.Burst = 1
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = SystemClock
+	}
+	return &Limiter{
+		cfg:    cfg,
+		tokens: float64(cfg.Burst),
+		last:   cfg.Clock.Now(),
+	}
+}
+
+func WithLimiter(limiter *Limiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// Wait blocks until a send slot is available, the context is done, or (in
+// coalescing mode) a newer caller supersedes this one. With a shared bucket
+// the newer caller may be in another process.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil || l.cfg.MinInterval <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	bucket, release, err := l.share(ctx)
+	if err != nil {
+		l.mu.Unlock()
+		return err
+	}
+	now := l.cfg.Clock.Now()
+	l.refill(now)
+
+	if l.pending == nil && l.tokens >= 1 {
+		l.tokens--
+		err := release()
+		l.mu.Unlock()
+		return err
+	}
+
+	if l.cfg.FailFast {
+		wait := l.untilNextToken()
+		if l.pending != nil {
+			wait = l.pending.ready.Sub(now) + l.cfg.MinInterval
+		}
+		err := release()
+		l.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		return &RateLimitError{Wait: wait}
+	}
+
+	w := &limiterWaiter{superseded: make(chan struct{}), ticket: strconv.FormatUint(rand.Uint64(), 36)}
+	switch {
+	case l.cfg.Coalesce && l.pending != nil:
+		w.ready = l.pending.ready
+		l.pending.done = true
+		close(l.pending.superseded)
+	case l.cfg.Coalesce && bucket.Pending != "" && bucket.Ready.After(now):
+		// Take over the slot another process is waiting for.
+		w.ready = bucket.Ready
+	default:
+		w.ready = now.Add(l.untilNextToken())
+		l.tokens--
+	}
+	if l.cfg.Coalesce {
+		l.pending = w
+		bucket.Pending, bucket.Ready = w.ticket, w.ready
+	}
+	wait := w.ready.Sub(now)
+	err = release()
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	var timer <-chan time.Time
+	if wait > 0 {
+		timer = l.cfg.Clock.After(wait)
+	} else {
+		ready := make(chan time.Time, 1)
+		ready <- now
+		timer = ready
+	}
+
+	select {
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if !w.done {
+			w.done = true
+			if l.pending == w {
+				l.pending = nil
+			}
+			// Give the slot back unless another process took it over.
+			if bucket, release, err := l.share(context.WithoutCancel(ctx)); err == nil {
+				if !l.cfg.Coalesce || bucket.Pending == w.ticket {
+					bucket.Pending = ""
+					l.tokens++
+				}
+				_ = release()
+			}
+		}
+		return ctx.Err()
+	case <-w.superseded:
+		return ErrSuperseded
+	case <-timer:
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if w.done {
+			return ErrSuperseded
+		}
+		w.done = true
+		if l.pending == w {
+			l.pending = nil
+		}
+		if !l.cfg.Coalesce || l.cfg.Shared == nil {
+			return nil
+		}
+		bucket, release, err := l.share(ctx)
+		if err != nil {
+			return err
+		}
+		superseded := bucket.Pending != w.ticket
+		if !superseded {
+			bucket.Pending = ""
+		}
+		if err := release(); err != nil {
+			return err
+		}
+		if superseded {
+			return ErrSuperseded
+		}
+		return nil
+	}
+}
+
+// share loads the shared bucket, if any, into l. The returned function
+// saves l back into it. Both are called with l.mu held.
+func (l *Limiter) share(ctx context.Context) (*Bucket, func() error, error) {
+	if l.cfg.Shared == nil {
+		return &Bucket{}, func() error { return nil }, nil
+	}
+	bucket, release, err := l.cfg.Shared.Lock(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !bucket.Last.IsZero() {
+		l.tokens, l.last = bucket.Tokens, bucket.Last
+	}
+	return bucket, func() error {
+		bucket.Tokens, bucket.Last = l.tokens, l.last
+		return release()
+	}, nil
+}
+
+func (l *Limiter) refill(now time.Time) {
+	elapsed := now.Sub(l.last)
+	if elapsed <= 0 {
+		return
+	}
+	l.last = now
+	l.tokens += float64(elapsed) / float64(l.cfg.MinInterval)
+	if burst := float64(l.cfg.Burst); l.tokens > burst {
+		l.tokens = burst
+	}
+}
+
+func (l *Limiter) untilNextToken() time.Duration {
+	if l.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - l.tokens) * float64(l.cfg.MinInterval))
+}
@@ -0,0 +1,269 @@
+package vestaboard
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeTimer
+}
+
+type fakeTimer struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeTimer{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	remaining := c.waiters[:0]
+	for _, w := range c.waiters {
+		if !w.at.After(c.now) {
+			w.ch <- c.now
+			continue
+		}
+		remaining = append(remaining, w)
+	}
+	c.waiters = remaining
+}
+
+func (c *fakeClock) pendingTimers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+func waitForTimers(t *testing.T, clock *fakeClock, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for clock.pendingTimers() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d timers", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLimiterBlocksUntilSlotIsFree(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	limiter := NewLimiter(LimiterConfig{MinInterval: 15 * time.Second, Clock: clock})
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("first wait: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- limiter.Wait(context.Background()) }()
+	waitForTimers(t, clock, 1)
+
+	clock.Advance(10 * time.Second)
+	select {
+	case err := <-done:
+		t.Fatalf("wait returned early: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	clock.Advance(5 * time.Second)
+	if err := <-done; err != nil {
+		t.Fatalf("second wait: %v", err)
+	}
+}
+
+func TestLimiterFailFast(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	limiter := NewLimiter(LimiterConfig{MinInterval: 15 * time.Second, FailFast: true, Clock: clock})
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("first wait: %v", err)
+	}
+	clock.Advance(5 * time.Second)
+
+	err := limiter.Wait(context.Background())
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("expected RateLimitError, got %v", err)
+	}
+	if rateErr.Wait != 10*time.Second {
+		t.Fatalf("wait = %v, want 10s", rateErr.Wait)
+	}
+
+	clock.Advance(10 * time.Second)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("wait after refill: %v", err)
+	}
+}
+
+type memoryBucket struct {
+	mu     sync.Mutex
+	bucket Bucket
+}
+
+func (m *memoryBucket) Lock(context.Context) (*Bucket, func() error, error) {
+	m.mu.Lock()
+	bucket := m.bucket
+	return &bucket, func() error {
+		m.bucket = bucket
+		m.mu.Unlock()
+		return nil
+	}, nil
+}
+
+func TestLimiterSharedBucket(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	shared := &memoryBucket{}
+	first := NewLimiter(LimiterConfig{MinInterval: 15 * time.Second, FailFast: true, Clock: clock, Shared: shared})
+	second := NewLimiter(LimiterConfig{MinInterval: 15 * time.Second, FailFast: true, Clock: clock, Shared: shared})
+
+	if err := first.Wait(context.Background()); err != nil {
+		t.Fatalf("first limiter: %v", err)
+	}
+	clock.Advance(5 * time.Second)
+	var rateErr *RateLimitError
+	if err := second.Wait(context.Background()); !errors.As(err, &rateErr) || rateErr.Wait != 10*time.Second {
+		t.Fatalf("second limiter: got %v, want a 10s RateLimitError", err)
+	}
+	clock.Advance(10 * time.Second)
+	if err := second.Wait(context.Background()); err != nil {
+		t.Fatalf("second limiter after refill: %v", err)
+	}
+}
+
+func TestLimiterCoalescesAcrossSharedBucket(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	shared := &memoryBucket{}
+	newLimiter := func() *Limiter {
+		return NewLimiter(LimiterConfig{MinInterval: 15 * time.Second, Coalesce: true, Clock: clock, Shared: shared})
+	}
+	if err := newLimiter().Wait(context.Background()); err != nil {
+		t.Fatalf("first wait: %v", err)
+	}
+
+	older := make(chan error, 1)
+	go func() { older <- newLimiter().Wait(context.Background()) }()
+	waitForTimers(t, clock, 1)
+	newer := make(chan error, 1)
+	go func() { newer <- newLimiter().Wait(context.Background()) }()
+	waitForTimers(t, clock, 2)
+
+	clock.Advance(15 * time.Second)
+	if err := <-older; !errors.Is(err, ErrSuperseded) {
+		t.Fatalf("older wait = %v, want ErrSuperseded", err)
+	}
+	if err := <-newer; err != nil {
+		t.Fatalf("newer wait: %v", err)
+	}
+}
+
+func TestLimiterBurst(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	limiter := NewLimiter(LimiterConfig{MinInterval: time.Minute, Burst: 2, FailFast: true, Clock: clock})
+
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("wait %d: %v", i, err)
+		}
+	}
+	if err := limiter.Wait(context.Background()); err == nil {
+		t.Fatal("expected third send to be limited")
+	}
+}
+
+func TestLimiterCoalescingDropsSupersededSends(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	limiter := NewLimiter(LimiterConfig{MinInterval: 15 * time.Second, Coalesce: true, Clock: clock})
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("first wait: %v", err)
+	}
+
+	older := make(chan error, 1)
+	go func() { older <- limiter.Wait(context.Background()) }()
+	waitForTimers(t, clock, 1)
+
+	newer := make(chan error, 1)
+	go func() { newer <- limiter.Wait(context.Background()) }()
+
+	if err := <-older; !errors.Is(err, ErrSuperseded) {
+		t.Fatalf("older wait = %v, want ErrSuperseded", err)
+	}
+	waitForTimers(t, clock, 2)
+
+	clock.Advance(15 * time.Second)
+	if err := <-newer; err != nil {
+		t.Fatalf("newer wait: %v", err)
+	}
+}
+
+func TestLimiterContextCancel(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	limiter := NewLimiter(LimiterConfig{MinInterval: time.Minute, Clock: clock})
+	_ = limiter.Wait(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- limiter.Wait(ctx) }()
+	waitForTimers(t, clock, 1)
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+}
+
+func TestSendCharactersUsesLimiter(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	limiter := NewLimiter(LimiterConfig{MinInterval: time.Minute, FailFast: true, Clock: clock})
+	_ = limiter.Wait(context.Background())
+
+	client, err := NewClient("abc123", WithLimiter(limiter))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	client.baseURL = "http://127.0.0.1:0"
+
+	var rateErr *RateLimitError
+	if err := client.SendCharacters(context.Background(), [][]int{{1}}); !errors.As(err, &rateErr) {
+		t.Fatalf("expected RateLimitError before any request, got %v", err)
+	}
+}
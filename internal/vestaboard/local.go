@@ -0,0 +1,176 @@
+package vestaboard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	board "vbcli/vestaboard"
+)
+
+const (
+	localPort                 = "7000"
+	localMessagePath          = "/local-api/message"
+	localEnablementPath       = "/local-api/enablement"
+	localHeaderName           = "X-Vestaboard-Local-Api-Key"
+	localEnablementHeaderName = "X-Vestaboard-Local-Api-Enablement-Token"
+)
+
+var (
+	errMissingLocalKey  = errors.New("VESTABOARD_LOCAL_API_KEY is not set")
+	errMissingLocalHost = errors.New("a Local API host is required")
+)
+
+// LocalClient talks to a board on the local network through the Vestaboard
+// Local API. Transitions are not part of the Local API; VBML formatting
+// still goes through the VBML service.
+type LocalClient struct {
+	client *Client
+}
+
+func NewLocalClient(host, apiKey string, options ...Option) (*LocalClient, error) {
+	apiKey = strings.TrimSpace(apiKey)
+	if apiKey == "" {
+		return nil, errMissingLocalKey
+	}
+	baseURL, err := localBaseURL(host)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newClient(&Client{
+		baseURL:    baseURL,
+		vbmlURL:    vbmlBaseURL,
+		token:      apiKey,
+		authHeader: localHeaderName,
+		service:    "vestaboard local API",
+	}, options)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalClient{client: client}, nil
+}
+
+// EnableLocalAPI performs the one-time enablement handshake and returns the
+// Local API key issued by the board.
+func EnableLocalAPI(ctx context.Context, host, enablementToken string, options ...Option) (string, error) {
+	enablementToken = strings.TrimSpace(enablementToken)
+	if enablementToken == "" {
+		return "", errors.New("an enablement token is required")
+	}
+	baseURL, err := localBaseURL(host)
+	if err != nil {
+		return "", err
+	}
+	client, err := newClient(&Client{
+		baseURL:    baseURL,
+		vbmlURL:    vbmlBaseURL,
+		token:      enablementToken,
+		authHeader: localEnablementHeaderName,
+		service:    "vestaboard local API",
+	}, options)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := client.do(ctx, http.MethodPost, client.baseURL+localEnablementPath, nil, true)
+	if err != nil {
+		return "", err
+	}
+	var response struct {
+		APIKey string `json:"apiKey"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("decode API response: %w", err)
+	}
+	if response.APIKey == "" {
+		return "", errors.New("local API enablement returned no apiKey")
+	}
+	return response.APIKey, nil
+}
+
+func (l *LocalClient) SendCharacters(ctx context.Context, characters [][]int) error {
+	if err := l.client.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	body, err := json.Marshal(characters)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	_, err = l.client.do(ctx, http.MethodPost, l.client.baseURL+localMessagePath, body, true)
+	return err
+}
+
+func (l *LocalClient) GetCurrent(ctx context.Context) ([]byte, error) {
+	return l.client.do(ctx, http.MethodGet, l.client.baseURL+localMessagePath, nil, true)
+}
+
+func (l *LocalClient) GetCurrentState(ctx context.Context) (*board.BoardState, error) {
+	body, err := l.GetCurrent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLocalBoardState(body)
+}
+
+func (l *LocalClient) FormatMessage(ctx context.Context, message, model, align, justify string) ([][]int, error) {
+	return l.client.FormatMessage(ctx, message, model, align, justify)
+}
+
+func (l *LocalClient) SetTransition(context.Context, string, string) error {
+	return fmt.Errorf("set transition: %w", board.ErrNotSupported)
+}
+
+func (l *LocalClient) GetTransition(context.Context) ([]byte, error) {
+	return nil, fmt.Errorf("get transition: %w", board.ErrNotSupported)
+}
+
+// ParseLocalBoardState decodes a Local API read response. Firmware returns
+// either a bare characters matrix or one wrapped in a "message" object.
+func ParseLocalBoardState(body []byte) (*board.BoardState, error) {
+	trimmed := bytes.TrimSpace(body)
+	var layout [][]int
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &layout); err != nil {
+			return nil, fmt.Errorf("decode API response: %w", err)
+		}
+	} else {
+		var payload struct {
+			Message [][]int `json:"message"`
+		}
+		if err := json.Unmarshal(trimmed, &payload); err != nil {
+			return nil, fmt.Errorf("decode API response: %w", err)
+		}
+		layout = payload.Message
+	}
+	if len(layout) == 0 {
+		return nil, errors.New("local API returned no message")
+	}
+
+	rows, columns := board.Dimensions(layout)
+	return &board.BoardState{
+		Layout:  layout,
+		Model:   board.InferModel(rows, columns),
+		Rows:    rows,
+		Columns: columns,
+		Raw:     body,
+	}, nil
+}
+
+func localBaseURL(host string) (string, error) {
+	host = strings.TrimRight(strings.TrimSpace(host), "/")
+	if host == "" {
+		return "", errMissingLocalHost
+	}
+	if strings.Contains(host, "://") {
+		return host, nil
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(strings.Trim(host, "[]"), localPort)
+	}
+	return "http://" + host, nil
+}
@@ -0,0 +1,128 @@
+package vestaboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	board "vbcli/vestaboard"
+)
+
+func TestLocalClientSendCharacters(t *testing.T) {
+	t.Parallel()
+
+	var gotKey, gotPath string
+	var gotBody [][]int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get(localHeaderName)
+		gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client, err := NewLocalClient(server.URL, "local-key", WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("new local client: %v", err)
+	}
+	if err := client.SendCharacters(context.Background(), [][]int{{1, 2}, {3, 4}}); err != nil {
+		t.Fatalf("send characters: %v", err)
+	}
+	if gotKey != "local-key" || gotPath != localMessagePath {
+		t.Fatalf("key %q, path %q", gotKey, gotPath)
+	}
+	if len(gotBody) != 2 || gotBody[1][1] != 4 {
+		t.Fatalf("unexpected body: %#v", gotBody)
+	}
+}
+
+func TestLocalClientGetCurrentState(t *testing.T) {
+	t.Parallel()
+
+	for _, body := range []string{`[[1,2],[3,4]]`, `{"message":[[1,2],[3,4]]}`} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(body))
+		}))
+
+		client, err := NewLocalClient(server.URL, "local-key", WithHTTPClient(server.Client()))
+		if err != nil {
+			t.Fatalf("new local client: %v", err)
+		}
+		state, err := client.GetCurrentState(context.Background())
+		server.Close()
+		if err != nil {
+			t.Fatalf("%s: get current state: %v", body, err)
+		}
+		if state.Rows != 2 || state.Layout[1][0] != 3 {
+			t.Fatalf("%s: unexpected state: %#v", body, state)
+		}
+	}
+}
+
+func TestLocalClientTransitionsUnsupported(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewLocalClient("vestaboard.local", "local-key")
+	if err != nil {
+		t.Fatalf("new local client: %v", err)
+	}
+	if err := client.SetTransition(context.Background(), "wave", "fast"); !errors.Is(err, board.ErrNotSupported) {
+		t.Fatalf("got %v, want ErrNotSupported", err)
+	}
+}
+
+func TestEnableLocalAPI(t *testing.T) {
+	t.Parallel()
+
+	var gotToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotToken = r.Header.Get(localEnablementHeaderName)
+		if r.URL.Path != localEnablementPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"message":"Local API enabled","apiKey":"issued-key"}`))
+	}))
+	defer server.Close()
+
+	key, err := EnableLocalAPI(context.Background(), server.URL, "enable-me", WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("enable local API: %v", err)
+	}
+	if key != "issued-key" || gotToken != "enable-me" {
+		t.Fatalf("key %q, token %q", key, gotToken)
+	}
+}
+
+func TestLocalBaseURL(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"vestaboard.local":       "http://vestaboard.local:7000",
+		"192.168.1.20":           "http://192.168.1.20:7000",
+		"192.168.1.20:8080":      "http://192.168.1.20:8080",
+		"https://board.example/": "https://board.example",
+	}
+	for input, want := range tests {
+		got, err := localBaseURL(input)
+		if err != nil || got != want {
+			t.Fatalf("localBaseURL(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := localBaseURL(" "); err == nil {
+		t.Fatal("expected error for empty host")
+	}
+}
+
+func TestNewLocalClientMissingKey(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewLocalClient("vestaboard.local", ""); err == nil {
+		t.Fatal("expected missing key error")
+	}
+}
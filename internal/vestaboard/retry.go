@@ -0,0 +1,128 @@
+package vestaboard
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	// maxRetryDelay bounds the backoff when MaxDelay is zero.
+	maxRetryDelay = time.Hour
+)
+
+// RetryPolicy controls how the client retries failed requests. The zero value
+// makes a single attempt.
+//
+// Idempotent requests are retried on any retryable APIError. The message POST
+// is only retried when the server provably did not act on it: a 429 or 503
+// response, or a connection that could not be established.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int
+	// BaseDelay is the backoff before the second attempt; it doubles on
+	// every further attempt. Defaults to 500ms.
+	BaseDelay time.Duration
+	// MaxDelay caps a single wait. A Retry-After longer than MaxDelay ends
+	// the retries instead of being shortened. Zero leaves only a one-hour
+	// ceiling on backoff.
+	MaxDelay time.Duration
+}
+
+func (p RetryPolicy) nextDelay(attempt int, err error, idempotent bool, jitter func() float64) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !apiErr.Retryable() {
+		return 0, false
+	}
+	if !idempotent && !rejectedBeforeProcessing(apiErr) {
+		return 0, false
+	}
+
+	if apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode == http.StatusServiceUnavailable {
+		if wait, ok := parseRetryAfter(apiErr.Header.Get("Retry-After"), time.Now()); ok {
+			if p.MaxDelay > 0 && wait > p.MaxDelay {
+				return 0, false
+			}
+			return wait, true
+		}
+	}
+
+	base := p.BaseDelay
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+	// Double in a loop rather than shift, so large attempt numbers cannot
+	// overflow.
+	delay := base
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxRetryDelay)
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if jitter == nil {
+		jitter = rand.Float64
+	}
+	// Equal jitter: keep half of the delay and randomise the rest.
+	half := delay / 2
+	return half + time.Duration(jitter()*float64(delay-half)), true
+}
+
+func rejectedBeforeProcessing(apiErr *APIError) bool {
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case 0:
+		var opErr *net.OpError
+		return errors.As(apiErr.Err, &opErr) && opErr.Op == "dial"
+	default:
+		return false
+	}
+}
+
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	when, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	wait := when.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	return wait, true
+}
+
+func (c *Client) sleepFor(ctx context.Context, d time.Duration) error {
+	if c.sleep != nil {
+		return c.sleep(ctx, d)
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,173 @@
+package vestaboard
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newRetryTestClient(url string, httpClient *http.Client, policy RetryPolicy, slept *[]time.Duration) *Client {
+	return &Client{
+		baseURL:    url,
+		vbmlURL:    url,
+		token:      "abc123",
+		httpClient: httpClient,
+		retry:      policy,
+		jitter:     func() float64 { return 1 },
+		sleep: func(_ context.Context, d time.Duration) error {
+			*slept = append(*slept, d)
+			return nil
+		},
+	}
+}
+
+func TestRetryIdempotentOnServerError(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"transition":"wave"}`))
+	}))
+	defer server.Close()
+
+	var slept []time.Duration
+	client := newRetryTestClient(server.URL, server.Client(), RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond}, &slept)
+
+	if _, err := client.GetTransition(context.Background()); err != nil {
+		t.Fatalf("get transition: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("calls = %d, want 3", calls.Load())
+	}
+	if len(slept) != 2 || slept[0] != 100*time.Millisecond || slept[1] != 200*time.Millisecond {
+		t.Fatalf("unexpected backoff: %v", slept)
+	}
+}
+
+func TestRetryBackoffDoesNotOverflow(t *testing.T) {
+	t.Parallel()
+
+	policy := RetryPolicy{MaxAttempts: 1000}
+	err := &APIError{StatusCode: http.StatusBadGateway, Category: CategoryServer}
+	for _, attempt := range []int{1, 2, 40, 64, 65, 999} {
+		delay, ok := policy.nextDelay(attempt, err, true, func() float64 { return 1 })
+		if !ok || delay <= 0 || delay > time.Hour {
+			t.Fatalf("attempt %d: delay %s, retry %v", attempt, delay, ok)
+		}
+	}
+	if delay, _ := policy.nextDelay(999, err, true, func() float64 { return 1 }); delay != time.Hour {
+		t.Fatalf("delay %s, want the one-hour ceiling", delay)
+	}
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var slept []time.Duration
+	client := newRetryTestClient(server.URL, server.Client(), RetryPolicy{MaxAttempts: 2, MaxDelay: 10 * time.Second}, &slept)
+
+	if err := client.SendCharacters(context.Background(), [][]int{{1}}); err != nil {
+		t.Fatalf("send characters: %v", err)
+	}
+	if len(slept) != 1 || slept[0] != 7*time.Second {
+		t.Fatalf("unexpected waits: %v", slept)
+	}
+}
+
+func TestRetryAfterBeyondMaxDelayGivesUp(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	var slept []time.Duration
+	client := newRetryTestClient(server.URL, server.Client(), RetryPolicy{MaxAttempts: 5, MaxDelay: 30 * time.Second}, &slept)
+
+	err := client.SendCharacters(context.Background(), [][]int{{1}})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Category != CategoryRateLimited {
+		t.Fatalf("expected rate-limited error, got %v", err)
+	}
+	if len(slept) != 0 {
+		t.Fatalf("expected no waits, got %v", slept)
+	}
+}
+
+func TestRetrySkipsUnsafeSendOnServerError(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	var slept []time.Duration
+	client := newRetryTestClient(server.URL, server.Client(), RetryPolicy{MaxAttempts: 3}, &slept)
+
+	if err := client.SendCharacters(context.Background(), [][]int{{1}}); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("calls = %d, want 1", calls.Load())
+	}
+}
+
+func TestRetryDoesNotRetryInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	var slept []time.Duration
+	client := newRetryTestClient(server.URL, server.Client(), RetryPolicy{MaxAttempts: 3}, &slept)
+
+	if _, err := client.FormatMessage(context.Background(), "hi", "flagship", "center", "center"); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("calls = %d, want 1", calls.Load())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got, ok := parseRetryAfter("3", now); !ok || got != 3*time.Second {
+		t.Fatalf("seconds form = %v, %v", got, ok)
+	}
+	if got, ok := parseRetryAfter("Wed, 01 Jan 2025 00:00:10 GMT", now); !ok || got != 10*time.Second {
+		t.Fatalf("date form = %v, %v", got, ok)
+	}
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Fatal("expected invalid value to be rejected")
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -9,7 +10,9 @@ import (
 
 func main() {
 	if err := cmd.NewRootCmd(os.Stdin, os.Stdout, os.Stderr).Execute(); err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
+		if !errors.Is(err, cmd.ErrChanged) && !errors.Is(err, cmd.ErrNotAllowed) {
+			_, _ = fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		}
+		os.Exit(cmd.ExitCode(err))
 	}
 }
@@ -0,0 +1,29 @@
+// Package vestaboard defines the Board interface that vbcli's commands
+// program against, the BoardState it reads, and an in-memory FakeBoard for
+// tests. The Cloud and Local API clients implement Board.
+package vestaboard
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotSupported is returned for operations a backend cannot perform.
+var ErrNotSupported = errors.New("operation not supported by this backend")
+
+// Board is implemented by every backend vbcli can drive: the Cloud API
+// client, the Local API client and the in-memory FakeBoard. Backends that
+// cannot perform an operation return an error wrapping ErrNotSupported.
+type Board interface {
+	SendCharacters(ctx context.Context, characters [][]int) error
+	GetCurrentState(ctx context.Context) (*BoardState, error)
+	SetTransition(ctx context.Context, transitionType, transitionSpeed string) error
+	GetTransition(ctx context.Context) ([]byte, error)
+}
+
+// Formatter renders template text into a characters matrix.
+type Formatter interface {
+	FormatMessage(ctx context.Context, message, model, align, justify string) ([][]int, error)
+}
+
+var _ Board = (*FakeBoard)(nil)
@@ -0,0 +1,130 @@
+package vestaboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FakeBoard is an in-memory Board for tests and dry runs. Every successful
+// send replaces the current layout and is recorded in Sent.
+type FakeBoard struct {
+	mu              sync.Mutex
+	layout          [][]int
+	id              string
+	createdAt       time.Time
+	sends           int
+	transition      string
+	transitionSpeed string
+
+	// Sent holds every characters matrix accepted by SendCharacters.
+	Sent [][][]int
+	// SendErr, when set, is returned by SendCharacters instead of sending.
+	SendErr error
+	// GetErr, when set, is returned by GetCurrentState instead of the layout.
+	GetErr error
+	// Now defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewFakeBoard returns a FakeBoard showing layout. A nil layout leaves the
+// board without a current message until the first send.
+func NewFakeBoard(layout [][]int) *FakeBoard {
+	fake := &FakeBoard{transition: "classic", transitionSpeed: "fast"}
+	if layout != nil {
+		fake.layout = cloneLayout(layout)
+		fake.id = "fake-0"
+		fake.createdAt = fake.now()
+	}
+	return fake
+}
+
+func (f *FakeBoard) SendCharacters(_ context.Context, characters [][]int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.SendErr != nil {
+		return f.SendErr
+	}
+	f.sends++
+	f.layout = cloneLayout(characters)
+	f.id = fmt.Sprintf("fake-%d", f.sends)
+	f.createdAt = f.now()
+	f.Sent = append(f.Sent, cloneLayout(characters))
+	return nil
+}
+
+func (f *FakeBoard) GetCurrentState(context.Context) (*BoardState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.GetErr != nil {
+		return nil, f.GetErr
+	}
+	if f.layout == nil {
+		return nil, errors.New("fake board has no current message")
+	}
+
+	layout := cloneLayout(f.layout)
+	encoded, err := json.Marshal(layout)
+	if err != nil {
+		return nil, fmt.Errorf("encode layout: %w", err)
+	}
+	raw, err := json.Marshal(map[string]any{
+		"currentMessage": map[string]any{
+			"id":        f.id,
+			"layout":    string(encoded),
+			"createdAt": f.createdAt.UnixMilli(),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode state: %w", err)
+	}
+
+	rows, columns := Dimensions(layout)
+	return &BoardState{
+		ID:        f.id,
+		CreatedAt: f.createdAt,
+		Layout:    layout,
+		Model:     InferModel(rows, columns),
+		Rows:      rows,
+		Columns:   columns,
+		Raw:       raw,
+	}, nil
+}
+
+func (f *FakeBoard) SetTransition(_ context.Context, transitionType, transitionSpeed string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.transition = transitionType
+	f.transitionSpeed = transitionSpeed
+	return nil
+}
+
+func (f *FakeBoard) GetTransition(context.Context) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return json.Marshal(map[string]string{
+		"transition":      f.transition,
+		"transitionSpeed": f.transitionSpeed,
+	})
+}
+
+func (f *FakeBoard) now() time.Time {
+	if f.Now != nil {
+		return f.Now()
+	}
+	return time.Now()
+}
+
+func cloneLayout(layout [][]int) [][]int {
+	if layout == nil {
+		return nil
+	}
+	out := make([][]int, len(layout))
+	for i, row := range layout {
+		out[i] = append([]int(nil), row...)
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+package vestaboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFakeBoardSendAndRead(t *testing.T) {
+	t.Parallel()
+
+	fake := NewFakeBoard(nil)
+	if _, err := fake.GetCurrentState(context.Background()); err == nil {
+		t.Fatal("expected error before the first send")
+	}
+
+	fixed := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake.Now = func() time.Time { return fixed }
+
+	characters := [][]int{{1, 2}, {3, 4}}
+	if err := fake.SendCharacters(context.Background(), characters); err != nil {
+		t.Fatalf("send characters: %v", err)
+	}
+	characters[0][0] = 99
+
+	state, err := fake.GetCurrentState(context.Background())
+	if err != nil {
+		t.Fatalf("get current state: %v", err)
+	}
+	if state.ID != "fake-1" || state.Layout[0][0] != 1 || !state.CreatedAt.Equal(fixed) {
+		t.Fatalf("unexpected state: %#v", state)
+	}
+
+	if len(fake.Sent) != 1 {
+		t.Fatalf("sent = %d, want 1", len(fake.Sent))
+	}
+}
+
+func TestFakeBoardSendErr(t *testing.T) {
+	t.Parallel()
+
+	fake := NewFakeBoard([][]int{{0}})
+	fake.SendErr = errors.New("boom")
+	if err := fake.SendCharacters(context.Background(), [][]int{{1}}); err == nil {
+		t.Fatal("expected injected error")
+	}
+	if len(fake.Sent) != 0 {
+		t.Fatal("failed send must not be recorded")
+	}
+}
+
+func TestFakeBoardGetErr(t *testing.T) {
+	t.Parallel()
+
+	fake := NewFakeBoard([][]int{{0}})
+	fake.GetErr = errors.New("boom")
+	if _, err := fake.GetCurrentState(context.Background()); err == nil {
+		t.Fatal("expected injected error")
+	}
+}
+
+func TestFakeBoardTransition(t *testing.T) {
+	t.Parallel()
+
+	fake := NewFakeBoard(nil)
+	if err := fake.SetTransition(context.Background(), "wave", "gentle"); err != nil {
+		t.Fatalf("set transition: %v", err)
+	}
+	body, err := fake.GetTransition(context.Background())
+	if err != nil {
+		t.Fatalf("get transition: %v", err)
+	}
+	if string(body) != `{"transition":"wave","transitionSpeed":"gentle"}` {
+		t.Fatalf("unexpected body: %s", body)
+	}
+}
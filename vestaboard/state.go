@@ -0,0 +1,45 @@
+package vestaboard
+
+import "time"
+
+const (
+	ModelFlagship = "flagship"
+	ModelNote     = "note"
+)
+
+// BoardState is the typed form of a board read. The Cloud API returns
+// currentMessage.layout as a JSON string that itself holds the characters
+// matrix; BoardState carries it already decoded, and Raw holds the response
+// as received.
+type BoardState struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt,omitzero"`
+	Layout    [][]int   `json:"layout"`
+	Model     string    `json:"model,omitempty"`
+	Rows      int       `json:"rows"`
+	Columns   int       `json:"columns"`
+	Raw       []byte    `json:"-"`
+}
+
+// Dimensions returns the row count and the widest row of a characters matrix.
+func Dimensions(layout [][]int) (rows, columns int) {
+	for _, row := range layout {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	return len(layout), columns
+}
+
+// InferModel maps board dimensions to a model name, or "" when they match
+// neither the flagship (6x22) nor the note (3x15).
+func InferModel(rows, columns int) string {
+	switch {
+	case rows == 6 && columns == 22:
+		return ModelFlagship
+	case rows == 3 && columns == 15:
+		return ModelNote
+	default:
+		return ""
+	}
+}
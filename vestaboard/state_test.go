@@ -0,0 +1,17 @@
+package vestaboard
+
+import "testing"
+
+func TestInferModel(t *testing.T) {
+	t.Parallel()
+
+	if got := InferModel(3, 15); got != ModelNote {
+		t.Fatalf("got %q, want %q", got, ModelNote)
+	}
+	if got := InferModel(6, 22); got != ModelFlagship {
+		t.Fatalf("got %q, want %q", got, ModelFlagship)
+	}
+	if got := InferModel(2, 2); got != "" {
+		t.Fatalf("got %q, want empty", got)
+	}
+}